@@ -0,0 +1,170 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// AccessTokenClaims represents claims of JWT access token.
+// See: https://datatracker.ietf.org/doc/html/rfc9068#section-2.2
+type AccessTokenClaims struct {
+	RegisteredClaims
+
+	// ClientID claim identifies the client to which the token was issued.
+	ClientID string `json:"client_id"`
+
+	// AuthTime claim is the time when the end-user authentication occurred.
+	AuthTime *NumericDate `json:"auth_time,omitempty"`
+
+	// ACR claim is an authentication context class reference.
+	ACR string `json:"acr,omitempty"`
+
+	// AMR claim is a list of authentication methods references.
+	AMR []string `json:"amr,omitempty"`
+
+	// Confirmation claim binds the token to a key, see DPoP.
+	Confirmation *Confirmation `json:"cnf,omitempty"`
+
+	// Scope claim is a space-separated list of granted scopes.
+	Scope string `json:"scope,omitempty"`
+
+	// Groups, Roles and Entitlements claims describe authorization attributes of the subject.
+	// See: https://datatracker.ietf.org/doc/html/rfc9068#section-2.2.3.1
+	Groups       []string `json:"groups,omitempty"`
+	Roles        []string `json:"roles,omitempty"`
+	Entitlements []string `json:"entitlements,omitempty"`
+}
+
+// Confirmation represents `cnf` claim.
+// See: https://datatracker.ietf.org/doc/html/rfc7800
+type Confirmation struct {
+	// JKT is a JWK SHA-256 Thumbprint of a key the token is bound to.
+	// See: https://datatracker.ietf.org/doc/html/rfc9449#section-6.1
+	JKT string `json:"jkt,omitempty"`
+
+	// JWK is a public key the token is bound to.
+	JWK *JWK `json:"jwk,omitempty"`
+}
+
+// HasScope reports whether token has a given scope.
+func (c *AccessTokenClaims) HasScope(scope string) bool {
+	for _, s := range strings.Fields(c.Scope) {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRequired checks presence of claims required by RFC 9068.
+func (c *AccessTokenClaims) validateRequired(errs *claimErrors) {
+	errs.require(c.Issuer != "", "iss")
+	errs.require(c.ExpiresAt != nil, "exp")
+	errs.require(len(c.Audience) != 0, "aud")
+	errs.require(c.Subject != "", "sub")
+	errs.require(c.ClientID != "", "client_id")
+	errs.require(c.IssuedAt != nil, "iat")
+	errs.require(c.ID != "", "jti")
+}
+
+// AccessTokenBuilder is used to create JWT access tokens.
+// Safe to use concurrently.
+type AccessTokenBuilder struct {
+	builder *Builder
+}
+
+// NewAccessTokenBuilder returns new instance of AccessTokenBuilder.
+// Header `typ` is always set to `at+jwt`.
+func NewAccessTokenBuilder(signer Signer, opts ...BuilderOption) *AccessTokenBuilder {
+	opts = append(opts[:len(opts):len(opts)], WithType(TypeAccessToken))
+	return &AccessTokenBuilder{
+		builder: NewBuilder(signer, opts...),
+	}
+}
+
+// Build checks that all required claims are present and builds a token.
+// Claims set by builder options like WithTTL and WithTokenID are taken into account.
+func (b *AccessTokenBuilder) Build(claims *AccessTokenClaims) (*Token, error) {
+	if !b.builder.claims.isSet() {
+		var errs claimErrors
+		claims.validateRequired(&errs)
+		if err := errs.err(); err != nil {
+			return nil, err
+		}
+		return b.builder.Build(claims)
+	}
+
+	rawClaims, err := b.builder.encodeClaims(claims)
+	if err != nil {
+		return nil, err
+	}
+	var final AccessTokenClaims
+	if err := json.Unmarshal(rawClaims, &final); err != nil {
+		return nil, err
+	}
+
+	var errs claimErrors
+	final.validateRequired(&errs)
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+	return b.builder.buildEncoded(rawClaims)
+}
+
+// AccessTokenValidator validates JWT access tokens.
+// See: https://datatracker.ietf.org/doc/html/rfc9068#section-4
+type AccessTokenValidator struct {
+	// Issuer is an expected `iss` claim, required.
+	Issuer string
+
+	// Audience is an expected `aud` value (resource server identifier), required.
+	Audience string
+
+	// Types are allowed `typ` header values, only `at+jwt` is allowed if empty.
+	// Use AnyType for issuers that don't set explicit type, see WithAllowedTypes.
+	Types []string
+
+	// Leeway is an allowed clock skew for time claims.
+	Leeway time.Duration
+
+	// Now returns current time, time.Now is used if nil.
+	Now func() time.Time
+}
+
+// Parse decodes an access token, verifies it's signature and validates claims.
+func (v *AccessTokenValidator) Parse(raw []byte, verifier Verifier) (*AccessTokenClaims, error) {
+	token, err := Parse(raw, verifier)
+	if err != nil {
+		return nil, err
+	}
+	return v.validate(token)
+}
+
+// Validate implements Validator interface.
+func (v *AccessTokenValidator) Validate(ctx context.Context, token *Token) error {
+	_, err := v.validate(token)
+	return err
+}
+
+func (v *AccessTokenValidator) validate(token *Token) (*AccessTokenClaims, error) {
+	if err := checkType(token.Header(), typesOr(v.Types, TypeAccessToken)); err != nil {
+		return nil, err
+	}
+
+	var claims AccessTokenClaims
+	if err := token.DecodeClaims(&claims); err != nil {
+		return nil, err
+	}
+
+	var errs claimErrors
+	claims.validateRequired(&errs)
+	errs.check(claims.Issuer == "" || claims.IsIssuer(v.Issuer), "iss", ErrIssuerMismatch)
+	errs.check(len(claims.Audience) == 0 || claims.IsForAudience(v.Audience), "aud", ErrAudienceMismatch)
+	errs.checkTime(&claims.RegisteredClaims, nowFunc(v.Now), v.Leeway)
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
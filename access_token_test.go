@@ -0,0 +1,173 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAccessToken(t *testing.T) {
+	signer := must(NewSignerHS(HS256, hsKey256))
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+	builder := NewAccessTokenBuilder(signer, WithKeyID("key-1"))
+
+	token, err := builder.Build(newAccessTokenClaims())
+	mustOk(t, err)
+	mustEqual(t, token.Header().Type, "at+jwt")
+	mustEqual(t, token.Header().KeyID, "key-1")
+
+	validator := &AccessTokenValidator{
+		Issuer:   "https://as.example.com",
+		Audience: "https://rs.example.com",
+	}
+
+	claims, err := validator.Parse(token.Bytes(), verifier)
+	mustOk(t, err)
+	mustEqual(t, claims.ClientID, "client")
+	mustEqual(t, claims.HasScope("read"), true)
+	mustEqual(t, claims.HasScope("admin"), false)
+	mustEqual(t, claims.Roles, []string{"viewer"})
+
+	_, err = ParseAndValidate(context.Background(), token.Bytes(), verifier, validator)
+	mustOk(t, err)
+}
+
+func TestAccessTokenBuildMissingClaims(t *testing.T) {
+	builder := NewAccessTokenBuilder(must(NewSignerHS(HS256, hsKey256)))
+
+	testCases := []func(c *AccessTokenClaims){
+		func(c *AccessTokenClaims) { c.Issuer = "" },
+		func(c *AccessTokenClaims) { c.ExpiresAt = nil },
+		func(c *AccessTokenClaims) { c.Audience = nil },
+		func(c *AccessTokenClaims) { c.Subject = "" },
+		func(c *AccessTokenClaims) { c.ClientID = "" },
+		func(c *AccessTokenClaims) { c.IssuedAt = nil },
+		func(c *AccessTokenClaims) { c.ID = "" },
+	}
+
+	for _, tc := range testCases {
+		claims := newAccessTokenClaims()
+		tc(claims)
+
+		_, err := builder.Build(claims)
+		mustEqual(t, errors.Is(err, ErrMissingClaim), true)
+	}
+}
+
+func TestAccessTokenBuildAutoClaims(t *testing.T) {
+	signer := must(NewSignerHS(HS256, hsKey256))
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+	builder := NewAccessTokenBuilder(signer,
+		WithTTL(time.Minute), WithTokenID(), WithIssuer("https://as.example.com"), WithAudience("https://rs.example.com"))
+
+	token, err := builder.Build(&AccessTokenClaims{RegisteredClaims: RegisteredClaims{Subject: "user"}, ClientID: "client"})
+	mustOk(t, err)
+	mustEqual(t, token.Header().Type, TypeAccessToken)
+
+	validator := &AccessTokenValidator{
+		Issuer:   "https://as.example.com",
+		Audience: "https://rs.example.com",
+	}
+	claims, err := validator.Parse(token.Bytes(), verifier)
+	mustOk(t, err)
+	mustEqual(t, claims.Subject, "user")
+	mustEqual(t, claims.ID != "", true)
+
+	_, err = builder.Build(&AccessTokenClaims{ClientID: "client"})
+	mustEqual(t, errors.Is(err, ErrMissingClaim), true)
+}
+
+func TestAccessTokenValidator(t *testing.T) {
+	signer := must(NewSignerHS(HS256, hsKey256))
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+
+	validator := &AccessTokenValidator{
+		Issuer:   "https://as.example.com",
+		Audience: "https://rs.example.com",
+		Leeway:   time.Minute,
+	}
+
+	testCases := []struct {
+		opts    []BuilderOption
+		claims  func(c *AccessTokenClaims)
+		wantErr error
+	}{
+		{nil, func(c *AccessTokenClaims) {}, ErrTypeMismatch},
+		{[]BuilderOption{WithType("application/AT+JWT")}, func(c *AccessTokenClaims) {}, nil},
+		{[]BuilderOption{WithType("at+jwt")}, func(c *AccessTokenClaims) { c.ClientID = "" }, ErrMissingClaim},
+		{[]BuilderOption{WithType("at+jwt")}, func(c *AccessTokenClaims) { c.Issuer = "evil" }, ErrIssuerMismatch},
+		{[]BuilderOption{WithType("at+jwt")}, func(c *AccessTokenClaims) { c.Audience = Audience{"other"} }, ErrAudienceMismatch},
+		{
+			[]BuilderOption{WithType("at+jwt")},
+			func(c *AccessTokenClaims) { c.ExpiresAt = NewNumericDate(time.Now().Add(-2 * time.Minute)) },
+			ErrTokenExpired,
+		},
+		{
+			[]BuilderOption{WithType("at+jwt")},
+			func(c *AccessTokenClaims) { c.ExpiresAt = NewNumericDate(time.Now().Add(-30 * time.Second)) },
+			nil,
+		},
+		{
+			[]BuilderOption{WithType("at+jwt")},
+			func(c *AccessTokenClaims) { c.IssuedAt = NewNumericDate(time.Now().Add(time.Hour)) },
+			ErrTokenNotValidYet,
+		},
+	}
+
+	for _, tc := range testCases {
+		claims := newAccessTokenClaims()
+		tc.claims(claims)
+		token := must(NewBuilder(signer, tc.opts...).Build(claims))
+
+		_, err := validator.Parse(token.Bytes(), verifier)
+		mustEqual(t, errors.Is(err, tc.wantErr), true)
+	}
+}
+
+func TestAccessTokenValidatorTypes(t *testing.T) {
+	signer := must(NewSignerHS(HS256, hsKey256))
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+
+	testCases := []struct {
+		types   []string
+		typ     string
+		wantErr error
+	}{
+		{nil, "JWT", ErrTypeMismatch},
+		{[]string{AnyType}, "JWT", nil},
+		{[]string{AnyType}, "at+jwt", nil},
+		{[]string{"at+jwt", "JWT"}, "JWT", nil},
+		{[]string{"application/at+jwt"}, "at+jwt", nil},
+		{[]string{"JWT"}, "at+jwt", ErrTypeMismatch},
+	}
+
+	for _, tc := range testCases {
+		validator := &AccessTokenValidator{
+			Issuer:   "https://as.example.com",
+			Audience: "https://rs.example.com",
+			Types:    tc.types,
+		}
+		token := must(NewBuilder(signer, WithType(tc.typ)).Build(newAccessTokenClaims()))
+
+		_, err := validator.Parse(token.Bytes(), verifier)
+		mustEqual(t, err, tc.wantErr)
+	}
+}
+
+func newAccessTokenClaims() *AccessTokenClaims {
+	now := time.Now()
+	return &AccessTokenClaims{
+		RegisteredClaims: RegisteredClaims{
+			ID:        "token-id",
+			Issuer:    "https://as.example.com",
+			Audience:  Audience{"https://rs.example.com"},
+			Subject:   "user",
+			IssuedAt:  NewNumericDate(now),
+			ExpiresAt: NewNumericDate(now.Add(time.Hour)),
+		},
+		ClientID: "client",
+		Scope:    "read write",
+		Roles:    []string{"viewer"},
+	}
+}
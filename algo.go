@@ -55,6 +55,21 @@ func hashPayload(hash crypto.Hash, payload []byte) ([]byte, error) {
 	return signed, nil
 }
 
+// hashOf returns a hash function used by a given algorithm.
+// EdDSA uses SHA-512 as Ed25519 does internally.
+func hashOf(alg Algorithm) (crypto.Hash, error) {
+	switch alg {
+	case HS256, RS256, ES256, PS256:
+		return crypto.SHA256, nil
+	case HS384, RS384, ES384, PS384:
+		return crypto.SHA384, nil
+	case HS512, RS512, ES512, PS512, EdDSA:
+		return crypto.SHA512, nil
+	default:
+		return 0, ErrUnsupportedAlg
+	}
+}
+
 func constTimeAlgEqual(a, b Algorithm) bool {
 	return constTimeEqual(a.String(), b.String())
 }
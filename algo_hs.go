@@ -92,6 +92,10 @@ func (hs *HSAlg) verify(payload, signature []byte) error {
 }
 
 func (hs *HSAlg) sign(payload []byte) ([]byte, error) {
+	return hs.appendSign(nil, payload)
+}
+
+func (hs *HSAlg) appendSign(dst, payload []byte) ([]byte, error) {
 	hasher := hs.hashPool.Get().(hash.Hash)
 	defer func() {
 		hasher.Reset()
@@ -101,5 +105,5 @@ func (hs *HSAlg) sign(payload []byte) ([]byte, error) {
 	if _, err := hasher.Write(payload); err != nil {
 		return nil, err
 	}
-	return hasher.Sum(nil), nil
+	return hasher.Sum(dst), nil
 }
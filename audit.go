@@ -0,0 +1,187 @@
+package jwt
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// AuditSeverity is a severity of an audit finding.
+type AuditSeverity int
+
+// Audit severities in ascending order.
+const (
+	AuditInfo AuditSeverity = iota
+	AuditWarning
+	AuditCritical
+)
+
+func (s AuditSeverity) String() string {
+	switch s {
+	case AuditInfo:
+		return "info"
+	case AuditWarning:
+		return "warning"
+	case AuditCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// AuditFinding is a weakness reported by Audit.
+type AuditFinding struct {
+	Check    string // short check name, like "missing-exp"
+	Severity AuditSeverity
+	Message  string
+}
+
+func (f AuditFinding) String() string {
+	return f.Severity.String() + ": " + f.Check + ": " + f.Message
+}
+
+// AuditOption configures Audit.
+type AuditOption func(*auditConfig)
+
+type auditConfig struct {
+	key         []byte
+	maxLifetime time.Duration
+	now         time.Time
+}
+
+// WithAuditKey sets an HMAC key used to sign a token, so it's length can be checked.
+func WithAuditKey(key []byte) AuditOption {
+	return func(c *auditConfig) { c.key = key }
+}
+
+// WithAuditMaxLifetime sets a lifetime after which a token is reported as long-lived, 24 hours by default.
+func WithAuditMaxLifetime(d time.Duration) AuditOption {
+	return func(c *auditConfig) { c.maxLifetime = d }
+}
+
+// Audit reports weaknesses in a token according to JWT Best Current Practices.
+// Token is not rejected and it's signature is not verified, findings are for a human review.
+// See: https://datatracker.ietf.org/doc/html/rfc8725
+func Audit(token *Token, opts ...AuditOption) []AuditFinding {
+	if !token.isValid() {
+		return nil
+	}
+	cfg := auditConfig{maxLifetime: 24 * time.Hour, now: time.Now()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	a := &auditor{}
+	a.auditHeader(token, &cfg)
+	a.auditClaims(token, &cfg)
+	return a.findings
+}
+
+type auditor struct {
+	findings []AuditFinding
+}
+
+func (a *auditor) report(check string, severity AuditSeverity, msg string) {
+	a.findings = append(a.findings, AuditFinding{Check: check, Severity: severity, Message: msg})
+}
+
+func (a *auditor) auditHeader(token *Token, cfg *auditConfig) {
+	header := token.Header()
+
+	switch header.Algorithm {
+	case HS256, HS384, HS512:
+		// See: https://datatracker.ietf.org/doc/html/rfc7518#section-3.2
+		hash, _ := hashOf(header.Algorithm)
+		if cfg.key != nil && len(cfg.key) < hash.Size() {
+			a.report("weak-key", AuditCritical, "HMAC key is shorter than "+string(header.Algorithm)+" hash output")
+		}
+	}
+	if isPathOrURL(header.KeyID) {
+		a.report("suspicious-kid", AuditWarning, "key ID looks like a path or a URL: "+header.KeyID)
+	}
+
+	// Header struct has only well-known members, decode it again to find the rest.
+	var raw map[string]json.RawMessage
+	if b, err := b64DecodeString(string(token.HeaderPart())); err == nil && json.Unmarshal(b, &raw) == nil {
+		for _, name := range []string{"jku", "x5u"} {
+			if _, ok := raw[name]; ok {
+				a.report(name+"-header", AuditWarning, name+" header refers to a remote key, it must not be trusted without an allowlist")
+			}
+		}
+	}
+}
+
+func (a *auditor) auditClaims(token *Token, cfg *auditConfig) {
+	var claims RegisteredClaims
+	var members map[string]json.RawMessage
+	if token.DecodeClaims(&claims) != nil || token.DecodeClaims(&members) != nil {
+		a.report("invalid-claims", AuditWarning, "claims are not a JSON object with registered claims")
+		return
+	}
+
+	if claims.ExpiresAt == nil {
+		a.report("missing-exp", AuditWarning, "token has no expiration time")
+	} else {
+		start := cfg.now
+		switch {
+		case claims.IssuedAt != nil:
+			start = claims.IssuedAt.Time
+		case claims.NotBefore != nil:
+			start = claims.NotBefore.Time
+		}
+		if lifetime := claims.ExpiresAt.Sub(start); lifetime > cfg.maxLifetime {
+			a.report("long-lifetime", AuditWarning, "token lifetime "+lifetime.String()+" is longer than "+cfg.maxLifetime.String())
+		}
+	}
+	if claims.Issuer == "" {
+		a.report("missing-iss", AuditInfo, "token has no issuer")
+	}
+	if len(claims.Audience) == 0 {
+		a.report("missing-aud", AuditWarning, "token has no audience and can be used by any recipient")
+	}
+
+	header := token.Header()
+	if typ := expectedType(members); typ != "" && (header.Type == "" || hasType(header, TypeJWT)) {
+		a.report("generic-typ", AuditWarning, "token looks like "+typ+" but has no explicit type")
+	}
+}
+
+// expectedType returns an explicit type for claims of a well-known profile, if any.
+func expectedType(claims map[string]json.RawMessage) string {
+	has := func(names ...string) bool {
+		for _, name := range names {
+			if _, ok := claims[name]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch {
+	case has("events"):
+		var events map[string]json.RawMessage
+		if json.Unmarshal(claims["events"], &events) == nil {
+			if _, ok := events[BackChannelLogoutEvent]; ok {
+				return TypeLogout
+			}
+		}
+		return TypeSecEvent
+	case has("htm", "htu"):
+		return TypeDPoP
+	case has("sd_hash"):
+		return TypeKeyBinding
+	case has("response_type", "client_id"):
+		return TypeAuthzRequest
+	case has("client_id", "scope"):
+		return TypeAccessToken
+	default:
+		return ""
+	}
+}
+
+// isPathOrURL reports whether a key ID can be a path traversal or a URL.
+// See: https://datatracker.ietf.org/doc/html/rfc8725#section-3.10
+func isPathOrURL(kid string) bool {
+	return strings.HasPrefix(kid, "/") || strings.HasPrefix(kid, ".") ||
+		strings.Contains(kid, "..") || strings.Contains(kid, "://") || strings.ContainsRune(kid, '\\')
+}
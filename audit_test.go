@@ -0,0 +1,86 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAudit(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	key := []byte("0123456789abcdef0123456789abcdef")
+	good := `{"iss":"issuer","aud":"api","iat":1700000000,"exp":1700003600}`
+
+	testCases := []struct {
+		header string
+		claims string
+		opts   []AuditOption
+		want   []string
+	}{
+		{`{"alg":"ES256","typ":"JWT"}`, good, nil, nil},
+		{`{"alg":"ES256"}`, `{"iss":"issuer","aud":"api"}`, nil, []string{"missing-exp"}},
+		{`{"alg":"ES256"}`, `{"aud":"api","exp":1700003600}`, nil, []string{"missing-iss"}},
+		{`{"alg":"ES256"}`, `{"iss":"issuer","exp":1700003600}`, nil, []string{"missing-aud"}},
+		{`{"alg":"ES256"}`, `"string-claims"`, nil, []string{"invalid-claims"}},
+		{
+			`{"alg":"ES256"}`, `{"iss":"issuer","aud":"api","iat":1700000000,"exp":1800000000}`,
+			nil, []string{"long-lifetime"},
+		},
+		{
+			`{"alg":"ES256"}`, `{"iss":"issuer","aud":"api","nbf":1700000000,"exp":1700007200}`,
+			[]AuditOption{WithAuditMaxLifetime(time.Hour)}, []string{"long-lifetime"},
+		},
+		{
+			`{"alg":"ES256"}`, `{"iss":"issuer","aud":"api","exp":1900000000}`,
+			nil, []string{"long-lifetime"},
+		},
+		{`{"alg":"HS256"}`, good, []AuditOption{WithAuditKey([]byte("short"))}, []string{"weak-key"}},
+		{`{"alg":"HS256"}`, good, []AuditOption{WithAuditKey(key)}, nil},
+		{`{"alg":"HS512"}`, good, []AuditOption{WithAuditKey(key)}, []string{"weak-key"}},
+		{`{"alg":"ES256"}`, good, []AuditOption{WithAuditKey([]byte("short"))}, nil},
+		{`{"alg":"ES256","kid":"../../dev/null"}`, good, nil, []string{"suspicious-kid"}},
+		{`{"alg":"ES256","kid":"https://example.com/key"}`, good, nil, []string{"suspicious-kid"}},
+		{`{"alg":"ES256","kid":"ab/cd+ef=="}`, good, nil, nil},
+		{
+			`{"alg":"ES256","jku":"https://example.com/jwks","x5u":"https://example.com/cert"}`,
+			good, nil, []string{"jku-header", "x5u-header"},
+		},
+		{
+			`{"alg":"ES256","typ":"JWT"}`,
+			`{"iss":"issuer","aud":"api","iat":1700000000,"exp":1700003600,"client_id":"client","scope":"read"}`,
+			nil, []string{"generic-typ"},
+		},
+		{
+			`{"alg":"ES256","typ":"at+jwt"}`,
+			`{"iss":"issuer","aud":"api","iat":1700000000,"exp":1700003600,"client_id":"client","scope":"read"}`,
+			nil, nil,
+		},
+		{
+			`{"alg":"ES256"}`,
+			`{"iss":"issuer","aud":"api","iat":1700000000,"exp":1700003600,"events":{"http://schemas.openid.net/event/backchannel-logout":{}}}`,
+			nil, []string{"generic-typ"},
+		},
+	}
+
+	for _, tc := range testCases {
+		token := must(ParseNoVerify([]byte(bytesToBase64([]byte(tc.header)) + "." + bytesToBase64([]byte(tc.claims)) + ".c2ln")))
+
+		opts := append(tc.opts, func(c *auditConfig) { c.now = now })
+		var have []string
+		for _, f := range Audit(token, opts...) {
+			have = append(have, f.Check)
+		}
+		mustEqual(t, have, tc.want)
+	}
+}
+
+func TestAuditFinding(t *testing.T) {
+	token := must(ParseNoVerify([]byte(bytesToBase64([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		bytesToBase64([]byte(`{"iss":"issuer","aud":"api"}`)) + ".c2ln")))
+
+	findings := Audit(token, WithAuditKey([]byte("short")))
+	mustEqual(t, len(findings), 2)
+	mustEqual(t, findings[0].String(), "critical: weak-key: HMAC key is shorter than HS256 hash output")
+	mustEqual(t, findings[1].String(), "warning: missing-exp: token has no expiration time")
+
+	mustEqual(t, Audit(&Token{}), []AuditFinding(nil))
+}
@@ -0,0 +1,152 @@
+package jwt
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Bearer error codes.
+// See: https://datatracker.ietf.org/doc/html/rfc6750#section-3.1
+const (
+	BearerInvalidRequest    = "invalid_request"
+	BearerInvalidToken      = "invalid_token"
+	BearerInsufficientScope = "insufficient_scope"
+)
+
+// BearerError is an error response of a protected resource.
+// Validators can return it to control the response directly.
+// See: https://datatracker.ietf.org/doc/html/rfc6750#section-3
+type BearerError struct {
+	StatusCode  int
+	Code        string // empty if request has no token
+	Description string
+	Realm       string
+	Scope       string // space separated scopes required for insufficient_scope
+}
+
+// invalidTokenErrors are errors caused by a token, not by a server.
+var invalidTokenErrors = []error{
+	ErrInvalidFormat, ErrAudienceInvalidFormat, ErrDateInvalidFormat, ErrNotJWTType,
+	ErrInvalidKey, ErrInvalidDisclosure, ErrInvalidSignature, ErrAlgorithmMismatch, ErrUnsupportedAlg, ErrKeyNotFound,
+	ErrTokenExpired, ErrTokenNotValidYet, ErrLifetimeTooLong, ErrAuthTimeTooOld,
+	ErrMissingClaim, ErrUnexpectedClaim, ErrMissingID, ErrMissingExpiresAt,
+	ErrTokenRevoked, ErrTokenReplayed, ErrTypeMismatch,
+	ErrIssuerMismatch, ErrAudienceMismatch, ErrClientMismatch, ErrAuthorizedPartyMismatch,
+	ErrNonceMismatch, ErrHashMismatch, ErrProofMismatch,
+}
+
+// NewBearerError maps an error returned by parsing or validation to a Bearer error response.
+// Token errors result in 401 invalid_token, ErrInsufficientScope in 403 insufficient_scope,
+// ErrTokenNotFound in 401 without an error code, *BearerError is returned as a copy.
+// Other errors, including KeyFetchError, are not caused by a client and result in 500 without a challenge.
+//
+// If redact is true, description doesn't tell which check failed,
+// so verification details are not leaked to clients. Original error should be logged instead.
+func NewBearerError(err error, redact bool) *BearerError {
+	var bearerErr *BearerError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &bearerErr):
+		c := *bearerErr
+		return &c
+	case errors.Is(err, ErrTokenNotFound):
+		return &BearerError{StatusCode: http.StatusUnauthorized}
+	case errors.Is(err, ErrInsufficientScope):
+		return newBearerError(http.StatusForbidden, BearerInsufficientScope, err, redact,
+			"The access token has insufficient scope")
+	case isInvalidToken(err):
+		return newBearerError(http.StatusUnauthorized, BearerInvalidToken, err, redact,
+			"The access token is invalid")
+	default:
+		return &BearerError{StatusCode: http.StatusInternalServerError}
+	}
+}
+
+func newBearerError(status int, code string, err error, redact bool, generic string) *BearerError {
+	desc := generic
+	if !redact {
+		desc = err.Error()
+	}
+	return &BearerError{StatusCode: status, Code: code, Description: desc}
+}
+
+func isInvalidToken(err error) bool {
+	// key source failures may wrap ErrInvalidKey or ErrKeyNotFound.
+	var fetchErr *KeyFetchError
+	if errors.As(err, &fetchErr) {
+		return false
+	}
+	for _, target := range invalidTokenErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *BearerError) Error() string {
+	switch {
+	case e.Description != "":
+		return e.Description
+	case e.Code != "":
+		return e.Code
+	default:
+		return http.StatusText(e.StatusCode)
+	}
+}
+
+// Challenge returns `WWW-Authenticate` header value, empty for server errors.
+func (e *BearerError) Challenge() string {
+	if e.StatusCode != http.StatusUnauthorized && e.Code == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("Bearer")
+	sep := " "
+	param := func(name, value string) {
+		if value == "" {
+			return
+		}
+		b.WriteString(sep)
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(quoteSafe(value))
+		b.WriteByte('"')
+		sep = ", "
+	}
+	param("realm", e.Realm)
+	param("error", e.Code)
+	param("error_description", e.Description)
+	param("scope", e.Scope)
+	return b.String()
+}
+
+// Write writes status code and `WWW-Authenticate` header to a response.
+func (e *BearerError) Write(w http.ResponseWriter) {
+	if challenge := e.Challenge(); challenge != "" {
+		w.Header().Set("WWW-Authenticate", challenge)
+	}
+	w.WriteHeader(e.StatusCode)
+}
+
+// WriteBearerError maps an error with NewBearerError and writes it to a response.
+// Nothing is written if err is nil.
+func WriteBearerError(w http.ResponseWriter, err error, redact bool) {
+	if bearerErr := NewBearerError(err, redact); bearerErr != nil {
+		bearerErr.Write(w)
+	}
+}
+
+// quoteSafe removes characters not allowed in a quoted parameter value.
+// See: https://datatracker.ietf.org/doc/html/rfc6750#section-3
+func quoteSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' {
+			return -1
+		}
+		return r
+	}, s)
+}
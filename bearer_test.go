@@ -0,0 +1,112 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBearerError(t *testing.T) {
+	testCases := []struct {
+		err        error
+		redact     bool
+		wantStatus int
+		wantHeader string
+	}{
+		{
+			ErrTokenNotFound, false,
+			http.StatusUnauthorized, `Bearer`,
+		},
+		{
+			ErrInvalidSignature, false,
+			http.StatusUnauthorized, `Bearer error="invalid_token", error_description="signature is not valid"`,
+		},
+		{
+			ErrInvalidSignature, true,
+			http.StatusUnauthorized, `Bearer error="invalid_token", error_description="The access token is invalid"`,
+		},
+		{
+			ErrAlgorithmMismatch, false,
+			http.StatusUnauthorized, `Bearer error="invalid_token", error_description="token is signed by another algorithm"`,
+		},
+		{
+			&ValidationError{Claims: []*ClaimError{{Claim: "exp", Err: ErrTokenExpired}}}, false,
+			http.StatusUnauthorized, `Bearer error="invalid_token", error_description="token claims are not valid: exp: token is expired"`,
+		},
+		{
+			&ParseError{Segment: "header", Err: errors.New(`invalid character '"' in "value"`)}, false,
+			http.StatusUnauthorized, `Bearer error="invalid_token", error_description="token format is not valid: header: invalid character '' in value"`,
+		},
+		{
+			fmt.Errorf("wrapped: %w", ErrTokenRevoked), true,
+			http.StatusUnauthorized, `Bearer error="invalid_token", error_description="The access token is invalid"`,
+		},
+		{
+			ErrInvalidKey, false,
+			http.StatusUnauthorized, `Bearer error="invalid_token", error_description="key is not valid"`,
+		},
+		{
+			fmt.Errorf("wrapped: %w", ErrInvalidDisclosure), false,
+			http.StatusUnauthorized, `Bearer error="invalid_token", error_description="wrapped: disclosure is not valid"`,
+		},
+		{
+			ErrInsufficientScope, false,
+			http.StatusForbidden, `Bearer error="insufficient_scope", error_description="token scope is insufficient"`,
+		},
+		{
+			&BearerError{StatusCode: http.StatusBadRequest, Code: BearerInvalidRequest, Realm: "api"}, true,
+			http.StatusBadRequest, `Bearer realm="api", error="invalid_request"`,
+		},
+		{
+			ErrReplayCacheFull, false,
+			http.StatusInternalServerError, ``,
+		},
+		{
+			&HTTPStatusError{URL: "https://example.com/jwks", StatusCode: 503}, false,
+			http.StatusInternalServerError, ``,
+		},
+		{
+			ErrKeyNotFound, false,
+			http.StatusUnauthorized, `Bearer error="invalid_token", error_description="key not found"`,
+		},
+		{
+			&KeyFetchError{URL: "https://example.com/jwks", Err: ErrKeyNotFound}, false,
+			http.StatusInternalServerError, ``,
+		},
+		{
+			&KeyFetchError{URL: "https://example.com/jwks", Err: ErrInvalidKey}, false,
+			http.StatusInternalServerError, ``,
+		},
+	}
+
+	for _, tc := range testCases {
+		w := httptest.NewRecorder()
+		WriteBearerError(w, tc.err, tc.redact)
+
+		mustEqual(t, w.Code, tc.wantStatus)
+		mustEqual(t, w.Header().Get("WWW-Authenticate"), tc.wantHeader)
+	}
+
+	mustEqual(t, NewBearerError(nil, false), (*BearerError)(nil))
+}
+
+func TestBearerErrorCopy(t *testing.T) {
+	orig := &BearerError{StatusCode: http.StatusBadRequest, Code: BearerInvalidRequest}
+
+	bearerErr := NewBearerError(fmt.Errorf("wrapped: %w", orig), false)
+	bearerErr.Realm = "api"
+
+	mustEqual(t, *orig, BearerError{StatusCode: http.StatusBadRequest, Code: BearerInvalidRequest})
+}
+
+func TestBearerErrorChallenge(t *testing.T) {
+	bearerErr := NewBearerError(ErrInsufficientScope, true)
+	bearerErr.Realm = "example"
+	bearerErr.Scope = "read write"
+
+	mustEqual(t, bearerErr.Challenge(), `Bearer realm="example", error="insufficient_scope", error_description="The access token has insufficient scope", scope="read write"`)
+	mustEqual(t, bearerErr.Error(), "The access token has insufficient scope")
+	mustEqual(t, NewBearerError(ErrTokenNotFound, false).Error(), "Unauthorized")
+}
@@ -18,12 +18,60 @@ func WithContentType(cty string) BuilderOption {
 	return func(b *Builder) { b.header.ContentType = cty }
 }
 
+// WithJWK sets `jwk` header for token.
+func WithJWK(jwk *JWK) BuilderOption {
+	return func(b *Builder) { b.header.JWK = jwk }
+}
+
+// WithNonce sets `nonce` header for token.
+// See: https://datatracker.ietf.org/doc/html/rfc8555#section-6.5.2
+func WithNonce(nonce string) BuilderOption {
+	return func(b *Builder) { b.header.Nonce = nonce }
+}
+
+// Token types for `typ` header, used for explicit typing.
+// See: https://datatracker.ietf.org/doc/html/rfc8725#section-3.11
+const (
+	// TypeJWT is a generic type, it's set by default.
+	TypeJWT = "JWT"
+
+	// TypeAccessToken is a `typ` header of JWT access tokens.
+	TypeAccessToken = "at+jwt"
+
+	// TypeDPoP is a `typ` header of DPoP proofs.
+	TypeDPoP = "dpop+jwt"
+
+	// TypeSecEvent is a `typ` header of Security Event Tokens.
+	TypeSecEvent = "secevent+jwt"
+
+	// TypeLogout is a `typ` header of back-channel logout tokens.
+	TypeLogout = "logout+jwt"
+
+	// TypeKeyBinding is a `typ` header of SD-JWT key binding JWT.
+	TypeKeyBinding = "kb+jwt"
+
+	// TypeAuthzRequest is a `typ` header of authorization request objects.
+	TypeAuthzRequest = "oauth-authz-req+jwt"
+)
+
+// WithType sets `typ` header for token, see Type constants for common values.
+// Empty type omits the header.
+func WithType(typ string) BuilderOption {
+	return func(b *Builder) { b.header.Type = typ }
+}
+
+// WithoutType omits `typ` header which is "JWT" by default.
+func WithoutType() BuilderOption {
+	return WithType("")
+}
+
 // Builder is used to create a new token.
 // Safe to use concurrently.
 type Builder struct {
 	signer    Signer
 	header    Header
 	headerRaw []byte
+	claims    autoClaims
 }
 
 // NewBuilder returns new instance of Builder.
@@ -32,7 +80,7 @@ func NewBuilder(signer Signer, opts ...BuilderOption) *Builder {
 		signer: signer,
 		header: Header{
 			Algorithm: signer.Algorithm(),
-			Type:      "JWT",
+			Type:      TypeJWT,
 		},
 	}
 
@@ -44,49 +92,143 @@ func NewBuilder(signer Signer, opts ...BuilderOption) *Builder {
 	return b
 }
 
+// BuildWithHeader is like Build but applies options to this token only.
+// Signer and the builder itself are reused, header is re-encoded only if options change it.
+func (b *Builder) BuildWithHeader(claims any, opts ...BuilderOption) (*Token, error) {
+	tb := *b
+	for _, opt := range opts {
+		opt(&tb)
+	}
+	tb.header.Algorithm = b.header.Algorithm
+
+	if tb.header != b.header {
+		tb.headerRaw = encodeHeader(tb.header)
+	}
+	return tb.Build(claims)
+}
+
 // Build used to create and encode JWT with a provided claims.
 // If claims param is of type []byte or string then it's treated as a marshaled JSON.
 // In other words you can pass already marshaled claims.
 func (b *Builder) Build(claims any) (*Token, error) {
-	rawClaims, err := encodeClaims(claims)
+	rawClaims, err := b.encodeClaims(claims)
+	if err != nil {
+		return nil, err
+	}
+	return b.buildEncoded(rawClaims)
+}
+
+// buildEncoded builds a token with claims which are already encoded and populated by claims options.
+func (b *Builder) buildEncoded(rawClaims []byte) (*Token, error) {
+	raw, signature, err := b.appendToken(nil, rawClaims)
 	if err != nil {
 		return nil, err
 	}
 
 	lenH := len(b.headerRaw)
 	lenC := b64EncodedLen(len(rawClaims))
-	lenS := b64EncodedLen(b.signer.SignSize())
 
-	token := make([]byte, lenH+1+lenC+1+lenS)
-	idx := 0
-	idx = copy(token[idx:], b.headerRaw)
+	t := &Token{
+		raw:       raw[:len(raw):len(raw)],
+		dot1:      lenH,
+		dot2:      lenH + 1 + lenC,
+		header:    b.header,
+		claims:    rawClaims,
+		signature: signature,
+	}
+	return t, nil
+}
+
+// AppendBuild is like Build but appends encoded token to dst and returns the extended buffer.
+// Token is not allocated, dst with enough capacity can be reused between calls.
+func (b *Builder) AppendBuild(dst []byte, claims any) ([]byte, error) {
+	rawClaims, err := encodeClaims(claims)
+	if err != nil {
+		return dst, err
+	}
+	return b.AppendBuildRaw(dst, rawClaims)
+}
+
+// AppendBuildRaw is like AppendBuild but takes already marshaled claims.
+// For HS algorithms with no claims options it doesn't allocate if dst has enough capacity.
+func (b *Builder) AppendBuildRaw(dst, rawClaims []byte) ([]byte, error) {
+	if b.claims.isSet() {
+		var err error
+		if rawClaims, err = b.claims.apply(rawClaims); err != nil {
+			return dst, err
+		}
+	}
+
+	token, _, err := b.appendToken(dst, rawClaims)
+	if err != nil {
+		return dst, err
+	}
+	return token, nil
+}
+
+// appendSigner is implemented by signers which can write signature without allocation.
+type appendSigner interface {
+	appendSign(dst, payload []byte) ([]byte, error)
+}
+
+// appendToken appends 'header.claims.signature' to dst, raw signature is returned separately.
+func (b *Builder) appendToken(dst, rawClaims []byte) (token, signature []byte, err error) {
+	signSize := b.signer.SignSize()
+	lenH := len(b.headerRaw)
+	lenC := b64EncodedLen(len(rawClaims))
+	lenS := b64EncodedLen(signSize)
+	size := lenH + 1 + lenC + 1 + lenS
+
+	// reserve space after the token for a raw signature, if signer can write it there
+	signer, canAppend := b.signer.(appendSigner)
+	extra := 0
+	if canAppend {
+		extra = signSize
+	}
+	start := len(dst)
+	if cap(dst)-start < size+extra {
+		grown := make([]byte, start, start+size+extra)
+		copy(grown, dst)
+		dst = grown
+	}
+	buf := dst[start : start+size]
+
+	idx := copy(buf, b.headerRaw)
 
 	// add '.' and append encoded claims
-	token[idx] = '.'
+	buf[idx] = '.'
 	idx++
-	b64Encode(token[idx:], rawClaims)
+	b64Encode(buf[idx:], rawClaims)
 	idx += lenC
 
 	// calculate signature of already written 'header.claims'
-	rawSignature, err := b.signer.Sign(token[:idx])
+	if canAppend {
+		end := start + size
+		signature, err = signer.appendSign(dst[end:end:end+signSize], buf[:idx])
+	} else {
+		signature, err = b.signer.Sign(buf[:idx])
+	}
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// add '.' and append encoded signature
-	token[idx] = '.'
+	buf[idx] = '.'
 	idx++
-	b64Encode(token[idx:], rawSignature)
+	b64Encode(buf[idx:], signature)
 
-	t := &Token{
-		raw:       token,
-		dot1:      lenH,
-		dot2:      lenH + 1 + lenC,
-		header:    b.header,
-		claims:    rawClaims,
-		signature: rawSignature,
+	return dst[:start+size], signature, nil
+}
+
+func (b *Builder) encodeClaims(claims any) ([]byte, error) {
+	rawClaims, err := encodeClaims(claims)
+	if err != nil {
+		return nil, err
 	}
-	return t, nil
+	if b.claims.isSet() {
+		return b.claims.apply(rawClaims)
+	}
+	return rawClaims, nil
 }
 
 func encodeClaims(claims any) ([]byte, error) {
@@ -101,13 +243,22 @@ func encodeClaims(claims any) ([]byte, error) {
 }
 
 func encodeHeader(header Header) []byte {
-	if header.Type == "JWT" && header.ContentType == "" && header.KeyID == "" {
-		if h := predefinedHeaders[header.Algorithm]; h != "" {
+	if header.ContentType == "" && header.KeyID == "" && header.JWK == nil && header.Nonce == "" {
+		if header.Type == TypeJWT {
+			if h := predefinedHeaders[header.Algorithm]; h != "" {
+				return []byte(h)
+			}
+		}
+		if h := predefinedTypedHeaders[typedHeaderKey{header.Algorithm, header.Type}]; h != "" {
 			return []byte(h)
 		}
-		// another algorithm? encode below
+		// another algorithm or type? encode below
 	}
-	// returned err is always nil, see jwt.Header.MarshalJSON
+	return b64EncodeHeader(header)
+}
+
+func b64EncodeHeader(header Header) []byte {
+	// returned err is always nil, JWK contains only strings, see jwt.Header.MarshalJSON
 	buf, _ := header.MarshalJSON()
 
 	encoded := make([]byte, b64EncodedLen(len(buf)))
@@ -142,3 +293,21 @@ var predefinedHeaders = map[Algorithm]string{
 	PS384: "eyJhbGciOiJQUzM4NCIsInR5cCI6IkpXVCJ9",
 	PS512: "eyJhbGciOiJQUzUxMiIsInR5cCI6IkpXVCJ9",
 }
+
+type typedHeaderKey struct {
+	alg Algorithm
+	typ string
+}
+
+// predefinedTypedHeaders are encoded headers of every algorithm for common types and without a type.
+var predefinedTypedHeaders = func() map[typedHeaderKey]string {
+	types := []string{"", TypeAccessToken, TypeDPoP, TypeSecEvent, TypeLogout, TypeKeyBinding, TypeAuthzRequest}
+
+	headers := make(map[typedHeaderKey]string, len(types)*len(predefinedHeaders))
+	for alg := range predefinedHeaders {
+		for _, typ := range types {
+			headers[typedHeaderKey{alg, typ}] = string(b64EncodeHeader(Header{Algorithm: alg, Type: typ}))
+		}
+	}
+	return headers
+}()
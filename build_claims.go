@@ -0,0 +1,101 @@
+package jwt
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WithClock sets a time source for `iat`, `exp` and `nbf` claims set by builder, time.Now by default.
+func WithClock(now func() time.Time) BuilderOption {
+	return func(b *Builder) { b.claims.now = now }
+}
+
+// WithIssuedAt sets `iat` claim to the current time.
+func WithIssuedAt() BuilderOption {
+	return func(b *Builder) { b.claims.issuedAt = true }
+}
+
+// WithTTL sets `exp` claim to the current time plus ttl and `iat` claim to the current time.
+func WithTTL(ttl time.Duration) BuilderOption {
+	return func(b *Builder) {
+		b.claims.ttl = ttl
+		b.claims.issuedAt = true
+	}
+}
+
+// WithNotBefore sets `nbf` claim to the current time.
+func WithNotBefore() BuilderOption {
+	return func(b *Builder) { b.claims.notBefore = true }
+}
+
+// WithTokenID sets `jti` claim to a random 128-bit value.
+func WithTokenID() BuilderOption {
+	return func(b *Builder) { b.claims.id = true }
+}
+
+// WithIssuer sets `iss` claim.
+func WithIssuer(iss string) BuilderOption {
+	return func(b *Builder) { b.claims.issuer = iss }
+}
+
+// WithAudience sets `aud` claim.
+func WithAudience(aud ...string) BuilderOption {
+	return func(b *Builder) { b.claims.audience = aud }
+}
+
+// autoClaims are registered claims set by builder.
+// Claims provided by user take precedence, only missing or null claims are set.
+type autoClaims struct {
+	now       func() time.Time
+	ttl       time.Duration
+	issuedAt  bool
+	notBefore bool
+	id        bool
+	issuer    string
+	audience  Audience
+}
+
+func (c *autoClaims) isSet() bool {
+	return c.ttl != 0 || c.issuedAt || c.notBefore || c.id || c.issuer != "" || len(c.audience) != 0
+}
+
+func (c *autoClaims) apply(raw []byte) ([]byte, error) {
+	var claims map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &claims); err != nil || claims == nil {
+		return nil, ErrInvalidClaims
+	}
+
+	missing := func(name string) bool {
+		v, ok := claims[name]
+		return !ok || string(v) == "null"
+	}
+	set := func(name string, value any) {
+		// returned err is always nil, values are strings and numbers
+		claims[name], _ = json.Marshal(value)
+	}
+
+	now := nowFunc(c.now)
+	if c.issuer != "" && missing("iss") {
+		set("iss", c.issuer)
+	}
+	if len(c.audience) != 0 && missing("aud") {
+		set("aud", c.audience)
+	}
+	if c.ttl != 0 && missing("exp") {
+		set("exp", NewNumericDate(now.Add(c.ttl)))
+	}
+	if c.notBefore && missing("nbf") {
+		set("nbf", NewNumericDate(now))
+	}
+	if c.issuedAt && missing("iat") {
+		set("iat", NewNumericDate(now))
+	}
+	if c.id && missing("jti") {
+		id, err := newTokenID()
+		if err != nil {
+			return nil, err
+		}
+		set("jti", id)
+	}
+	return json.Marshal(claims)
+}
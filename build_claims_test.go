@@ -0,0 +1,91 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildAutoClaims(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	clock := func() time.Time { return now }
+	signer := must(NewSignerHS(HS256, hsKey256))
+
+	testCases := []struct {
+		opts   []BuilderOption
+		claims any
+		want   string
+	}{
+		{
+			[]BuilderOption{WithTTL(time.Hour)},
+			&RegisteredClaims{Subject: "user"},
+			`{"exp":1700003600,"iat":1700000000,"sub":"user"}`,
+		},
+		{
+			[]BuilderOption{WithIssuedAt(), WithNotBefore()},
+			map[string]any{"sub": "user"},
+			`{"iat":1700000000,"nbf":1700000000,"sub":"user"}`,
+		},
+		{
+			[]BuilderOption{WithIssuer("issuer"), WithAudience("aud")},
+			[]byte(`{"sub":"user"}`),
+			`{"aud":"aud","iss":"issuer","sub":"user"}`,
+		},
+		{
+			[]BuilderOption{WithAudience("a", "b")},
+			`{}`,
+			`{"aud":["a","b"]}`,
+		},
+		{
+			[]BuilderOption{WithTTL(time.Hour), WithIssuer("issuer")},
+			&RegisteredClaims{Issuer: "user-issuer", ExpiresAt: NewNumericDate(now.Add(time.Minute))},
+			`{"exp":1700000060,"iat":1700000000,"iss":"user-issuer"}`,
+		},
+		{
+			[]BuilderOption{WithIssuedAt()},
+			&DPoPClaims{ID: "id", Method: "GET", URL: "https://example.com"},
+			`{"htm":"GET","htu":"https://example.com","iat":1700000000,"jti":"id"}`,
+		},
+		{
+			nil,
+			`{"sub":"user"}`,
+			`{"sub":"user"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		opts := append(tc.opts, WithClock(clock))
+		token, err := NewBuilder(signer, opts...).Build(tc.claims)
+		mustOk(t, err)
+		mustEqual(t, string(token.Claims()), tc.want)
+	}
+}
+
+func TestBuildAutoClaimsTokenID(t *testing.T) {
+	builder := NewBuilder(must(NewSignerHS(HS256, hsKey256)), WithTokenID())
+
+	var first, second RegisteredClaims
+	mustOk(t, must(builder.Build(&RegisteredClaims{})).DecodeClaims(&first))
+	mustOk(t, must(builder.Build(&RegisteredClaims{})).DecodeClaims(&second))
+	mustEqual(t, len(first.ID), 22)
+	mustEqual(t, first.ID != second.ID, true)
+
+	var claims RegisteredClaims
+	mustOk(t, must(builder.Build(&RegisteredClaims{ID: "user-id"})).DecodeClaims(&claims))
+	mustEqual(t, claims.ID, "user-id")
+
+	mustOk(t, must(builder.BuildWithHeader(&RegisteredClaims{}, WithTTL(time.Minute))).DecodeClaims(&claims))
+	mustEqual(t, claims.ExpiresAt != nil && claims.IssuedAt != nil, true)
+
+	claims = RegisteredClaims{}
+	mustOk(t, must(builder.Build(&RegisteredClaims{})).DecodeClaims(&claims))
+	mustEqual(t, claims.ExpiresAt == nil, true)
+}
+
+func TestBuildAutoClaimsBad(t *testing.T) {
+	builder := NewBuilder(must(NewSignerHS(HS256, hsKey256)), WithIssuedAt())
+
+	for _, claims := range []any{`"string"`, []byte(`[1,2]`), `null`, `{`} {
+		_, err := builder.Build(claims)
+		mustEqual(t, err, ErrInvalidClaims)
+	}
+}
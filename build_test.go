@@ -2,6 +2,7 @@ package jwt
 
 import (
 	"errors"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -153,6 +154,36 @@ func TestBuildHeader(t *testing.T) {
 			[]BuilderOption{WithContentType("jwk+json")},
 			`{"alg":"RS512","typ":"JWT","cty":"jwk+json"}`,
 		},
+		{
+			must(NewSignerHS(HS256, key)),
+			[]BuilderOption{WithType("at+jwt")},
+			`{"alg":"HS256","typ":"at+jwt"}`,
+		},
+		{
+			must(NewSignerES(ES256, ecdsaPrivateKey256)),
+			[]BuilderOption{WithType(TypeDPoP)},
+			`{"alg":"ES256","typ":"dpop+jwt"}`,
+		},
+		{
+			must(NewSignerRS(RS256, rsaPrivateKey256)),
+			[]BuilderOption{WithType(TypeSecEvent), WithKeyID("test")},
+			`{"alg":"RS256","typ":"secevent+jwt","kid":"test"}`,
+		},
+		{
+			must(NewSignerHS(HS256, key)),
+			[]BuilderOption{WithType("custom+jwt")},
+			`{"alg":"HS256","typ":"custom+jwt"}`,
+		},
+		{
+			must(NewSignerHS(HS256, key)),
+			[]BuilderOption{WithoutType()},
+			`{"alg":"HS256"}`,
+		},
+		{
+			must(NewSignerEdDSA(ed25519PrivateKey)),
+			[]BuilderOption{WithoutType(), WithKeyID("test")},
+			`{"alg":"EdDSA","kid":"test"}`,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -165,6 +196,137 @@ func TestBuildHeader(t *testing.T) {
 	}
 }
 
+func TestBuildWithHeader(t *testing.T) {
+	signer := must(NewSignerHS(HS256, hsKey256))
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+	builder := NewBuilder(signer, WithKeyID("base"))
+
+	testCases := []struct {
+		opts []BuilderOption
+		want string
+	}{
+		{
+			nil,
+			`{"alg":"HS256","typ":"JWT","kid":"base"}`,
+		},
+		{
+			[]BuilderOption{WithKeyID("base")},
+			`{"alg":"HS256","typ":"JWT","kid":"base"}`,
+		},
+		{
+			[]BuilderOption{WithKeyID("other")},
+			`{"alg":"HS256","typ":"JWT","kid":"other"}`,
+		},
+		{
+			[]BuilderOption{WithNonce("n-1")},
+			`{"alg":"HS256","typ":"JWT","kid":"base","nonce":"n-1"}`,
+		},
+		{
+			[]BuilderOption{WithNonce("n-2"), WithoutType(), WithKeyID("")},
+			`{"alg":"HS256","nonce":"n-2"}`,
+		},
+		{
+			[]BuilderOption{WithNonce(`x"y\z`)},
+			`{"alg":"HS256","typ":"JWT","kid":"base","nonce":"x\"y\\z"}`,
+		},
+		{
+			[]BuilderOption{WithNonce(`n","alg":"none`), WithKeyID("k\n\"")},
+			`{"alg":"HS256","typ":"JWT","kid":"k\n\"","nonce":"n\",\"alg\":\"none"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		token, err := builder.BuildWithHeader(simplePayload, tc.opts...)
+		mustOk(t, err)
+		mustOk(t, verifier.Verify(token))
+
+		have := string(token.HeaderPart())
+		want := bytesToBase64([]byte(tc.want))
+		mustEqual(t, have, want)
+
+		parsed, err := Parse(token.Bytes(), verifier)
+		mustOk(t, err)
+		mustEqual(t, parsed.Header(), token.Header())
+	}
+
+	// builder header is not changed
+	token, err := builder.Build(simplePayload)
+	mustOk(t, err)
+	mustEqual(t, token.Header(), Header{Algorithm: HS256, Type: TypeJWT, KeyID: "base"})
+}
+
+func TestAppendBuild(t *testing.T) {
+	testCases := []struct {
+		signer   Signer
+		verifier Verifier
+	}{
+		{must(NewSignerHS(HS256, hsKey256)), must(NewVerifierHS(HS256, hsKey256))},
+		{must(NewSignerHS(HS512, hsKey512)), must(NewVerifierHS(HS512, hsKey512))},
+		{must(NewSignerRS(RS256, rsaPrivateKey256)), must(NewVerifierRS(RS256, rsaPublicKey256))},
+		{must(NewSignerES(ES256, ecdsaPrivateKey256)), must(NewVerifierES(ES256, ecdsaPublicKey256))},
+		{must(NewSignerEdDSA(ed25519PrivateKey)), must(NewVerifierEdDSA(ed25519PublicKey))},
+	}
+
+	for _, tc := range testCases {
+		builder := NewBuilder(tc.signer, WithKeyID("kid"))
+
+		dst := []byte("prefix ")
+		dst, err := builder.AppendBuild(dst, &RegisteredClaims{Subject: "struct"})
+		mustOk(t, err)
+		dst, err = builder.AppendBuildRaw(append(dst, ' '), []byte(`{"sub":"raw"}`))
+		mustOk(t, err)
+
+		parts := strings.Split(string(dst), " ")
+		mustEqual(t, len(parts), 3)
+		mustEqual(t, parts[0], "prefix")
+
+		token, err := Parse([]byte(parts[1]), tc.verifier)
+		mustOk(t, err)
+		mustEqual(t, token.Header(), Header{Algorithm: tc.signer.Algorithm(), Type: TypeJWT, KeyID: "kid"})
+		mustEqual(t, string(token.Claims()), `{"sub":"struct"}`)
+
+		token, err = Parse([]byte(parts[2]), tc.verifier)
+		mustOk(t, err)
+		mustEqual(t, string(token.Claims()), `{"sub":"raw"}`)
+	}
+
+	builder := NewBuilder(must(NewSignerHS(HS256, hsKey256)))
+	token := must(builder.Build(simplePayload))
+	mustEqual(t, string(must(builder.AppendBuild(nil, simplePayload))), token.String())
+
+	// space for a raw signature is reserved only for signers which write it into dst.
+	dst := must(NewBuilder(must(NewSignerEdDSA(ed25519PrivateKey))).AppendBuild(nil, simplePayload))
+	mustEqual(t, cap(dst), len(dst))
+
+	dst, err := NewBuilder(badSigner{}).AppendBuildRaw([]byte("prefix"), []byte(`{}`))
+	mustFail(t, err)
+	mustEqual(t, string(dst), "prefix")
+}
+
+func TestAppendBuildAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("pooled hashers are dropped in race builds")
+	}
+
+	builder := NewBuilder(must(NewSignerHS(HS256, hsKey256)))
+	claims := []byte(`{"jti":"id","iss":"issuer"}`)
+	buf := make([]byte, 0, 256)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = builder.AppendBuildRaw(buf[:0], claims)
+	})
+	mustEqual(t, allocs, 0.0)
+}
+
+func TestBuildPredefinedHeaders(t *testing.T) {
+	for alg, h := range predefinedHeaders {
+		mustEqual(t, h, string(b64EncodeHeader(Header{Algorithm: alg, Type: TypeJWT})))
+	}
+	for key, h := range predefinedTypedHeaders {
+		mustEqual(t, h, string(b64EncodeHeader(Header{Algorithm: key.alg, Type: key.typ})))
+	}
+}
+
 func TestBuildClaims(t *testing.T) {
 	key := []byte("somekey")
 	s := must(NewSignerHS(HS256, key))
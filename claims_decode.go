@@ -0,0 +1,428 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// DecodeRegisteredClaims decodes registered claims in a single pass without reflection.
+// Other claims are skipped and can be decoded later with DecodeClaims.
+// Result is the same as DecodeClaims with RegisteredClaims, JSON errors are returned as ParseError.
+func (t *Token) DecodeRegisteredClaims(dst *RegisteredClaims) error {
+	if err := decodeRegisteredClaims(t.claims, dst); err != nil {
+		return &ParseError{Segment: "claims", Err: err}
+	}
+	return nil
+}
+
+var errInvalidJSON = errors.New("invalid JSON")
+
+// maxJSONDepth is a nesting limit, same as in encoding/json.
+const maxJSONDepth = 10000
+
+// decodeRegisteredClaims follows encoding/json rules: keys are case-insensitive,
+// last duplicate wins, null keeps strings and resets dates, type errors don't stop decoding.
+// Values which are not plain strings or numbers are decoded by encoding/json.
+func decodeRegisteredClaims(data []byte, dst *RegisteredClaims) error {
+	s := claimsScanner{data: data}
+
+	s.skipSpace()
+	if s.skipLiteral("null") {
+		// same as encoding/json, null doesn't change claims
+		s.skipSpace()
+		if s.pos != len(data) {
+			return errInvalidJSON
+		}
+		return nil
+	}
+	if !s.consume('{') {
+		return errInvalidJSON
+	}
+
+	var typeErr error
+	s.skipSpace()
+	if !s.consume('}') {
+		for {
+			s.skipSpace()
+			key, escaped, ok := s.scanString()
+			if !ok {
+				return errInvalidJSON
+			}
+			s.skipSpace()
+			if !s.consume(':') {
+				return errInvalidJSON
+			}
+			s.skipSpace()
+			start := s.pos
+			if !s.skipValue(1) {
+				return errInvalidJSON
+			}
+
+			err := setRegisteredClaim(dst, key, escaped, data[start:s.pos])
+			if err != nil {
+				var jsonTypeErr *json.UnmarshalTypeError
+				if !errors.As(err, &jsonTypeErr) {
+					return err
+				}
+				if typeErr == nil {
+					typeErr = err
+				}
+			}
+
+			s.skipSpace()
+			if s.consume(',') {
+				continue
+			}
+			if s.consume('}') {
+				break
+			}
+			return errInvalidJSON
+		}
+	}
+
+	s.skipSpace()
+	if s.pos != len(data) {
+		return errInvalidJSON
+	}
+	return typeErr
+}
+
+func setRegisteredClaim(dst *RegisteredClaims, key []byte, escaped bool, value []byte) error {
+	if escaped {
+		var name string
+		if err := json.Unmarshal(quoted(key), &name); err != nil {
+			return err
+		}
+		key = []byte(name)
+	}
+
+	switch {
+	case isClaim(key, "exp"):
+		return decodeDate(&dst.ExpiresAt, value)
+	case isClaim(key, "nbf"):
+		return decodeDate(&dst.NotBefore, value)
+	case isClaim(key, "iat"):
+		return decodeDate(&dst.IssuedAt, value)
+	case isClaim(key, "iss"):
+		return decodeString(&dst.Issuer, value)
+	case isClaim(key, "sub"):
+		return decodeString(&dst.Subject, value)
+	case isClaim(key, "jti"):
+		return decodeString(&dst.ID, value)
+	case isClaim(key, "aud"):
+		return decodeAudience(&dst.Audience, value)
+	default:
+		return nil
+	}
+}
+
+func isClaim(key []byte, name string) bool {
+	return string(key) == name || bytes.EqualFold(key, []byte(name))
+}
+
+func decodeString(dst *string, value []byte) error {
+	if s, ok := plainString(value); ok {
+		*dst = string(s)
+		return nil
+	}
+	if string(value) == "null" {
+		return nil
+	}
+	return json.Unmarshal(value, dst)
+}
+
+func decodeDate(dst **NumericDate, value []byte) error {
+	if string(value) == "null" {
+		*dst = nil
+		return nil
+	}
+	if *dst == nil {
+		*dst = &NumericDate{}
+	}
+
+	if sec, ok := plainInt(value); ok {
+		**dst = NumericDate{time.Unix(sec, 0)}
+		return nil
+	}
+	if value[0] == '-' || (value[0] >= '0' && value[0] <= '9') {
+		f, err := strconv.ParseFloat(string(value), 64)
+		if err != nil {
+			return ErrDateInvalidFormat
+		}
+		sec, dec := math.Modf(f)
+		**dst = NumericDate{time.Unix(int64(sec), int64(dec*1e9))}
+		return nil
+	}
+	return (*dst).UnmarshalJSON(value)
+}
+
+func decodeAudience(dst *Audience, value []byte) error {
+	if s, ok := plainString(value); ok {
+		*dst = Audience{string(s)}
+		return nil
+	}
+	if value[0] != '[' {
+		return dst.UnmarshalJSON(value)
+	}
+
+	// count elements first, fall back for anything except plain strings
+	s := claimsScanner{data: value, pos: 1}
+	n := 0
+	for s.skipSpace(); !s.consume(']'); s.skipSpace() {
+		if n > 0 && !s.consume(',') {
+			return dst.UnmarshalJSON(value)
+		}
+		s.skipSpace()
+		start := s.pos
+		if _, escaped, ok := s.scanString(); !ok || escaped || !utf8.Valid(value[start:s.pos]) {
+			return dst.UnmarshalJSON(value)
+		}
+		n++
+	}
+
+	aud := make(Audience, 0, n)
+	s.pos = 1
+	for s.skipSpace(); !s.consume(']'); s.skipSpace() {
+		s.consume(',')
+		s.skipSpace()
+		str, _, _ := s.scanString()
+		aud = append(aud, string(str))
+	}
+	*dst = aud
+	return nil
+}
+
+// plainString returns a JSON string content if it has no escapes and is a valid UTF-8.
+func plainString(value []byte) ([]byte, bool) {
+	if len(value) < 2 || value[0] != '"' {
+		return nil, false
+	}
+	s := value[1 : len(value)-1]
+	if bytes.IndexByte(s, '\\') >= 0 || !utf8.Valid(s) {
+		return nil, false
+	}
+	return s, true
+}
+
+// plainInt parses an integer which is exactly representable as float64.
+func plainInt(value []byte) (int64, bool) {
+	digits := value
+	if len(digits) > 0 && digits[0] == '-' {
+		digits = digits[1:]
+	}
+	if len(digits) == 0 || len(digits) > 15 {
+		return 0, false
+	}
+
+	var n int64
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int64(c-'0')
+	}
+	if value[0] == '-' {
+		n = -n
+	}
+	return n, true
+}
+
+func quoted(s []byte) []byte {
+	q := make([]byte, 0, len(s)+2)
+	q = append(q, '"')
+	q = append(q, s...)
+	return append(q, '"')
+}
+
+// claimsScanner validates and skips JSON values according to RFC 8259.
+type claimsScanner struct {
+	data []byte
+	pos  int
+}
+
+func (s *claimsScanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (s *claimsScanner) consume(c byte) bool {
+	if s.pos < len(s.data) && s.data[s.pos] == c {
+		s.pos++
+		return true
+	}
+	return false
+}
+
+// scanString returns string content without quotes and reports whether it has escapes.
+func (s *claimsScanner) scanString() (str []byte, escaped, ok bool) {
+	if !s.consume('"') {
+		return nil, false, false
+	}
+	start := s.pos
+	for s.pos < len(s.data) {
+		c := s.data[s.pos]
+		switch {
+		case c == '"':
+			s.pos++
+			return s.data[start : s.pos-1], escaped, true
+		case c == '\\':
+			escaped = true
+			if !s.skipEscape() {
+				return nil, false, false
+			}
+		case c < 0x20:
+			return nil, false, false
+		default:
+			s.pos++
+		}
+	}
+	return nil, false, false
+}
+
+func (s *claimsScanner) skipEscape() bool {
+	s.pos++ // backslash
+	if s.pos >= len(s.data) {
+		return false
+	}
+	switch s.data[s.pos] {
+	case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+		s.pos++
+		return true
+	case 'u':
+		s.pos++
+		if len(s.data)-s.pos < 4 {
+			return false
+		}
+		for _, c := range s.data[s.pos : s.pos+4] {
+			if !isHex(c) {
+				return false
+			}
+		}
+		s.pos += 4
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *claimsScanner) skipValue(depth int) bool {
+	if depth > maxJSONDepth || s.pos >= len(s.data) {
+		return false
+	}
+
+	switch c := s.data[s.pos]; {
+	case c == '"':
+		_, _, ok := s.scanString()
+		return ok
+	case c == '{':
+		s.pos++
+		s.skipSpace()
+		if s.consume('}') {
+			return true
+		}
+		for {
+			s.skipSpace()
+			if _, _, ok := s.scanString(); !ok {
+				return false
+			}
+			s.skipSpace()
+			if !s.consume(':') {
+				return false
+			}
+			s.skipSpace()
+			if !s.skipValue(depth + 1) {
+				return false
+			}
+			s.skipSpace()
+			if s.consume('}') {
+				return true
+			}
+			if !s.consume(',') {
+				return false
+			}
+		}
+	case c == '[':
+		s.pos++
+		s.skipSpace()
+		if s.consume(']') {
+			return true
+		}
+		for {
+			s.skipSpace()
+			if !s.skipValue(depth + 1) {
+				return false
+			}
+			s.skipSpace()
+			if s.consume(']') {
+				return true
+			}
+			if !s.consume(',') {
+				return false
+			}
+		}
+	case c == 't':
+		return s.skipLiteral("true")
+	case c == 'f':
+		return s.skipLiteral("false")
+	case c == 'n':
+		return s.skipLiteral("null")
+	case c == '-' || (c >= '0' && c <= '9'):
+		return s.skipNumber()
+	default:
+		return false
+	}
+}
+
+func (s *claimsScanner) skipLiteral(lit string) bool {
+	if !bytes.HasPrefix(s.data[s.pos:], []byte(lit)) {
+		return false
+	}
+	s.pos += len(lit)
+	return true
+}
+
+func (s *claimsScanner) skipNumber() bool {
+	s.consume('-')
+	switch {
+	case s.consume('0'):
+	case s.skipDigits():
+	default:
+		return false
+	}
+	if s.consume('.') && !s.skipDigits() {
+		return false
+	}
+	if s.consume('e') || s.consume('E') {
+		if !s.consume('+') {
+			s.consume('-')
+		}
+		if !s.skipDigits() {
+			return false
+		}
+	}
+	return true
+}
+
+// skipDigits skips at least one digit.
+func (s *claimsScanner) skipDigits() bool {
+	start := s.pos
+	for s.pos < len(s.data) && s.data[s.pos] >= '0' && s.data[s.pos] <= '9' {
+		s.pos++
+	}
+	return s.pos > start
+}
+
+func isHex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
@@ -0,0 +1,77 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+)
+
+var registeredClaimsCorpus = []string{
+	`{}`,
+	` { } `,
+	`{"iss":"issuer","sub":"subject","jti":"id","aud":"aud","exp":1700000000,"nbf":1700000000,"iat":1700000000}`,
+	`{"aud":["a","b"],"exp":1700000000.5,"iat":-1.25,"nbf":1e9}`,
+	`{"aud":[],"custom":{"nested":[1,2.5e-3,true,false,null,{"a":"é"}]}}`,
+	`{"aud":[ "a" , "b\n" ]}`,
+	`{"aud":null}`,
+	`{"aud":1}`,
+	`{"aud":["a",1]}`,
+	`{"exp":null,"iss":null}`,
+	`{"exp":"1700000000"}`,
+	`{"exp":true}`,
+	`{"exp":1e400}`,
+	`{"exp":12345678901234567890}`,
+	`{"iss":1,"sub":"subject"}`,
+	`{"iss":"first","iss":"second"}`,
+	`{"EXP":5,"Iss":"upper","ſub":"long s"}`,
+	`{"\u0069ss":"escaped key","\u0073ub":1}`,
+	`{"iss":"a\ud800b","sub":"\"quoted\""}`,
+	`{"iss":"` + "\xff" + `"}`,
+	`{"iss":"a"`,
+	`{"iss":"a",}`,
+	`{"iss":"a"} x`,
+	`{"exp":01}`,
+	`{"exp":-}`,
+	`{"exp":1.}`,
+	`{"exp":.5}`,
+	`{"x":[1,]}`,
+	`{"x":"\x"}`,
+	`{"x":"\u12"}`,
+	`{"x":tru}`,
+	"{\"x\":\"\x01\"}",
+	`[]`,
+	`null`,
+	`"string"`,
+	``,
+}
+
+func TestDecodeRegisteredClaims(t *testing.T) {
+	for _, raw := range registeredClaimsCorpus {
+		token := &Token{claims: []byte(raw)}
+
+		var want, have RegisteredClaims
+		wantErr := token.DecodeClaims(&want)
+		haveErr := token.DecodeRegisteredClaims(&have)
+
+		if (wantErr == nil) != (haveErr == nil) {
+			t.Fatalf("%s: want err %v, have %v", raw, wantErr, haveErr)
+		}
+		if haveErr != nil {
+			mustEqual(t, errors.Is(haveErr, ErrInvalidFormat), true)
+			continue
+		}
+		mustEqual(t, have, want)
+	}
+}
+
+func TestDecodeRegisteredClaimsAllocs(t *testing.T) {
+	token := &Token{claims: []byte(`{"iss":"issuer","aud":"aud","exp":1700000000,"custom":{"a":[1,2,3]}}`)}
+
+	var claims RegisteredClaims
+	allocs := testing.AllocsPerRun(100, func() {
+		claims = RegisteredClaims{}
+		_ = token.DecodeRegisteredClaims(&claims)
+	})
+	// strings, audience and a date
+	mustEqual(t, allocs <= 5, true)
+	mustEqual(t, claims.IsForAudience("aud"), true)
+}
@@ -0,0 +1,160 @@
+package jwt
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ClientAssertionType is a `client_assertion_type` parameter for JWT client authentication.
+// See: https://datatracker.ietf.org/doc/html/rfc7523#section-2.2
+const ClientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// NewClientAssertion builds a client assertion for `private_key_jwt` client authentication.
+// Audience is a token endpoint URL or an authorization server issuer.
+// See: https://openid.net/specs/openid-connect-core-1_0.html#ClientAuthentication
+func NewClientAssertion(builder *Builder, clientID, audience string, ttl time.Duration) (*Token, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return builder.Build(&RegisteredClaims{
+		ID:        jti,
+		Issuer:    clientID,
+		Subject:   clientID,
+		Audience:  Audience{audience},
+		IssuedAt:  NewNumericDate(now),
+		ExpiresAt: NewNumericDate(now.Add(ttl)),
+	})
+}
+
+// ClientKeyRegistry resolves keys of registered clients.
+type ClientKeyRegistry interface {
+	// ClientVerifier returns a Verifier for client keys, KeySet can be used for clients with many keys.
+	ClientVerifier(ctx context.Context, clientID string) (Verifier, error)
+}
+
+// ClientKeyRegistryFunc is an adapter to use an ordinary function as a ClientKeyRegistry.
+type ClientKeyRegistryFunc func(ctx context.Context, clientID string) (Verifier, error)
+
+// ClientVerifier implements ClientKeyRegistry interface.
+func (f ClientKeyRegistryFunc) ClientVerifier(ctx context.Context, clientID string) (Verifier, error) {
+	return f(ctx, clientID)
+}
+
+// ClientAssertionValidator authenticates clients with JWT assertions, used by authorization servers.
+// See: https://datatracker.ietf.org/doc/html/rfc7523#section-3
+type ClientAssertionValidator struct {
+	// Registry resolves client keys, required.
+	Registry ClientKeyRegistry
+
+	// Replay rejects an already used assertion, required.
+	Replay *ReplayGuard
+
+	// Audiences are accepted `aud` values: token endpoint URL, issuer, etc.
+	Audiences []string
+
+	// MaxLifetime is a max allowed time till assertion expiration, default is 5 minutes.
+	MaxLifetime time.Duration
+
+	// Leeway is an allowed clock skew for time claims.
+	Leeway time.Duration
+
+	// Now returns current time, time.Now is used if nil.
+	Now func() time.Time
+}
+
+// ParseRequest authenticates a client by `client_assertion` and `client_assertion_type` form parameters.
+// If request has `client_id` parameter it must match the assertion.
+func (v *ClientAssertionValidator) ParseRequest(r *http.Request) (*RegisteredClaims, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	switch r.PostForm.Get("client_assertion_type") {
+	case ClientAssertionType:
+	case "":
+		return nil, ErrTokenNotFound
+	default:
+		return nil, ErrTypeMismatch
+	}
+
+	assertion := r.PostForm.Get("client_assertion")
+	if assertion == "" {
+		return nil, ErrTokenNotFound
+	}
+	return v.Parse(r.Context(), []byte(assertion), r.PostForm.Get("client_id"))
+}
+
+// Parse verifies a client assertion with the client keys and returns it's claims.
+// Subject of the claims is the authenticated client id.
+// If clientID is not empty the assertion must be issued by this client.
+func (v *ClientAssertionValidator) Parse(ctx context.Context, raw []byte, clientID string) (*RegisteredClaims, error) {
+	if v.Registry == nil || v.Replay == nil {
+		return nil, ErrInvalidConfig
+	}
+
+	token, err := ParseNoVerify(raw)
+	if err != nil {
+		return nil, err
+	}
+	var claims RegisteredClaims
+	if err := token.DecodeRegisteredClaims(&claims); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case claims.Issuer == "":
+		return nil, missingClaim("iss")
+	case !claims.IsSubject(claims.Issuer):
+		return nil, ErrClientMismatch
+	case clientID != "" && !claims.IsIssuer(clientID):
+		return nil, ErrClientMismatch
+	}
+
+	verifier, err := v.Registry.ClientVerifier(ctx, claims.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifier.Verify(token); err != nil {
+		return nil, err
+	}
+
+	maxLifetime := v.MaxLifetime
+	if maxLifetime == 0 {
+		maxLifetime = 5 * time.Minute
+	}
+	now := nowFunc(v.Now)
+
+	var errs claimErrors
+	errs.check(v.isForAudience(&claims), "aud", ErrAudienceMismatch)
+	errs.check(claims.ID != "", "jti", ErrMissingID)
+	errs.check(claims.ExpiresAt != nil, "exp", ErrMissingExpiresAt)
+	errs.checkTime(&claims, now, v.Leeway)
+	errs.check(claims.ExpiresAt == nil || !claims.ExpiresAt.After(now.Add(maxLifetime+v.Leeway)), "exp", ErrLifetimeTooLong)
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	// `jti` is unique per client.
+	if err := v.Replay.Use(replayKey(claims.Issuer, claims.ID), claims.ExpiresAt.Time); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+// replayKey returns an unambiguous replay key of a `jti` issued by iss.
+func replayKey(iss, jti string) string {
+	return strconv.Itoa(len(iss)) + ":" + iss + jti
+}
+
+func (v *ClientAssertionValidator) isForAudience(claims *RegisteredClaims) bool {
+	for _, aud := range v.Audiences {
+		if claims.IsForAudience(aud) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientAssertion(t *testing.T) {
+	ctx := context.Background()
+	builder := NewBuilder(must(NewSignerES(ES256, ecdsaPrivateKey256)))
+	validator := newTestClientAssertionValidator()
+
+	assertion, err := NewClientAssertion(builder, "client", "https://as.example.com/token", time.Minute)
+	mustOk(t, err)
+
+	claims, err := validator.Parse(ctx, assertion.Bytes(), "client")
+	mustOk(t, err)
+	mustEqual(t, claims.Subject, "client")
+
+	_, err = validator.Parse(ctx, assertion.Bytes(), "client")
+	mustEqual(t, err, ErrTokenReplayed)
+
+	another := must(NewClientAssertion(builder, "client", "https://as.example.com/token", time.Minute))
+	_, err = validator.Parse(ctx, another.Bytes(), "another-client")
+	mustEqual(t, err, ErrClientMismatch)
+}
+
+func TestReplayKey(t *testing.T) {
+	mustEqual(t, replayKey("a b", "c") != replayKey("a", "b c"), true)
+	mustEqual(t, replayKey("1:a", "b") != replayKey("1", ":ab"), true)
+	mustEqual(t, replayKey("client", "id"), "6:clientid")
+}
+
+func TestClientAssertionValidator(t *testing.T) {
+	ctx := context.Background()
+	signer := must(NewSignerES(ES256, ecdsaPrivateKey256))
+	now := time.Now()
+
+	testCases := []struct {
+		signer  Signer
+		claims  *RegisteredClaims
+		wantErr error
+	}{
+		{
+			signer,
+			&RegisteredClaims{ID: "1", Issuer: "client", Subject: "client", Audience: Audience{"https://as.example.com"}, ExpiresAt: NewNumericDate(now.Add(time.Minute))},
+			nil,
+		},
+		{
+			signer,
+			&RegisteredClaims{ID: "2", Issuer: "client", Subject: "another", Audience: Audience{"https://as.example.com"}, ExpiresAt: NewNumericDate(now.Add(time.Minute))},
+			ErrClientMismatch,
+		},
+		{
+			signer,
+			&RegisteredClaims{ID: "3", Subject: "client", Audience: Audience{"https://as.example.com"}, ExpiresAt: NewNumericDate(now.Add(time.Minute))},
+			ErrMissingClaim,
+		},
+		{
+			signer,
+			&RegisteredClaims{ID: "4", Issuer: "unknown", Subject: "unknown", Audience: Audience{"https://as.example.com"}, ExpiresAt: NewNumericDate(now.Add(time.Minute))},
+			ErrKeyNotFound,
+		},
+		{
+			must(NewSignerES(ES256, ecdsaPrivateKey256Another)),
+			&RegisteredClaims{ID: "5", Issuer: "client", Subject: "client", Audience: Audience{"https://as.example.com"}, ExpiresAt: NewNumericDate(now.Add(time.Minute))},
+			ErrInvalidSignature,
+		},
+		{
+			signer,
+			&RegisteredClaims{ID: "6", Issuer: "client", Subject: "client", Audience: Audience{"https://rs.example.com"}, ExpiresAt: NewNumericDate(now.Add(time.Minute))},
+			ErrAudienceMismatch,
+		},
+		{
+			signer,
+			&RegisteredClaims{Issuer: "client", Subject: "client", Audience: Audience{"https://as.example.com"}, ExpiresAt: NewNumericDate(now.Add(time.Minute))},
+			ErrMissingID,
+		},
+		{
+			signer,
+			&RegisteredClaims{ID: "8", Issuer: "client", Subject: "client", Audience: Audience{"https://as.example.com"}},
+			ErrMissingExpiresAt,
+		},
+		{
+			signer,
+			&RegisteredClaims{ID: "9", Issuer: "client", Subject: "client", Audience: Audience{"https://as.example.com"}, ExpiresAt: NewNumericDate(now.Add(-time.Minute))},
+			ErrTokenExpired,
+		},
+		{
+			signer,
+			&RegisteredClaims{ID: "10", Issuer: "client", Subject: "client", Audience: Audience{"https://as.example.com"}, ExpiresAt: NewNumericDate(now.Add(time.Hour))},
+			ErrLifetimeTooLong,
+		},
+	}
+
+	validator := newTestClientAssertionValidator()
+	for _, tc := range testCases {
+		token := must(NewBuilder(tc.signer).Build(tc.claims))
+
+		_, err := validator.Parse(ctx, token.Bytes(), "")
+		mustEqual(t, errors.Is(err, tc.wantErr), true)
+	}
+}
+
+func TestClientAssertionParseRequest(t *testing.T) {
+	builder := NewBuilder(must(NewSignerES(ES256, ecdsaPrivateKey256)))
+	assertion := must(NewClientAssertion(builder, "client", "https://as.example.com", time.Minute))
+
+	newReq := func(form url.Values) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/token", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return r
+	}
+
+	validator := newTestClientAssertionValidator()
+
+	_, err := validator.ParseRequest(newReq(url.Values{"client_assertion": {assertion.String()}}))
+	mustEqual(t, err, ErrTokenNotFound)
+
+	_, err = validator.ParseRequest(newReq(url.Values{
+		"client_assertion_type": {"urn:example:other"},
+		"client_assertion":      {assertion.String()},
+	}))
+	mustEqual(t, err, ErrTypeMismatch)
+
+	claims, err := validator.ParseRequest(newReq(url.Values{
+		"client_assertion_type": {ClientAssertionType},
+		"client_assertion":      {assertion.String()},
+		"client_id":             {"client"},
+	}))
+	mustOk(t, err)
+	mustEqual(t, claims.Issuer, "client")
+}
+
+func newTestClientAssertionValidator() *ClientAssertionValidator {
+	return &ClientAssertionValidator{
+		Registry: ClientKeyRegistryFunc(func(ctx context.Context, clientID string) (Verifier, error) {
+			if clientID != "client" {
+				return nil, ErrKeyNotFound
+			}
+			return NewVerifierES(ES256, ecdsaPublicKey256)
+		}),
+		Replay:    NewReplayGuard(100, time.Minute),
+		Audiences: []string{"https://as.example.com", "https://as.example.com/token"},
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/cristalhq/jwt/v5"
+)
+
+func runConvert(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("convert", stderr)
+	to := fs.String("to", "", "output format: pem or jwk, defaults to the other one")
+	public := fs.Bool("public", false, "output only a public key")
+	kid := fs.String("kid", "", "JWK key ID, defaults to the key thumbprint")
+	alg := fs.String("alg", "", "JWK algorithm")
+	use := fs.String("use", "", "JWK public key use: sig or enc")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	key, err := readKey(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	jwk, err := key.toJWK()
+	if err != nil {
+		return err
+	}
+	if *public {
+		jwk = jwk.Public()
+	}
+
+	format := *to
+	if format == "" {
+		format = "jwk"
+		if key.jwk != nil {
+			format = "pem"
+		}
+	}
+	if format == "jwk" {
+		if err := setJWKParams(jwk, *kid, jwt.Algorithm(*alg), *use); err != nil {
+			return err
+		}
+	}
+
+	out, err := encodeKey(jwk, format)
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(out)
+	return err
+}
+
+func runThumbprint(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("thumbprint", stderr)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	key, err := readKey(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	jwk, err := key.toJWK()
+	if err != nil {
+		return err
+	}
+	thumbprint, err := jwk.Thumbprint()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout, thumbprint)
+	return err
+}
+
+// readKey returns a key from a file argument or stdin.
+func readKey(args []string, stdin io.Reader) (*keyFile, error) {
+	data, err := readInput(args, stdin)
+	if err != nil {
+		return nil, err
+	}
+	return parseKey(data)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cristalhq/jwt/v5"
+)
+
+// timeClaims are printed as dates after claims.
+var timeClaims = []string{"iat", "nbf", "exp", "auth_time"}
+
+func runDecode(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("decode", stderr)
+	asJSON := fs.Bool("json", false, "print a single JSON object with header and claims")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	raw, err := readToken(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	token, err := jwt.ParseNoVerify(raw)
+	if err != nil {
+		return errInvalid{err}
+	}
+	// decode header again to keep members unknown to jwt.Header.
+	header, err := base64.RawURLEncoding.DecodeString(string(token.HeaderPart()))
+	if err != nil {
+		return errInvalid{err}
+	}
+
+	if *asJSON {
+		out, err := json.MarshalIndent(struct {
+			Header json.RawMessage `json:"header"`
+			Claims json.RawMessage `json:"claims"`
+		}{header, token.Claims()}, "", "  ")
+		if err != nil {
+			return errInvalid{err}
+		}
+		_, err = fmt.Fprintf(stdout, "%s\n", out)
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Header:\n%s\n\nClaims:\n%s\n", indentJSON(header), indentJSON(token.Claims()))
+
+	var claims map[string]json.RawMessage
+	if json.Unmarshal(token.Claims(), &claims) != nil {
+		return nil
+	}
+	var dates bytes.Buffer
+	for _, name := range timeClaims {
+		value, ok := claims[name]
+		if !ok {
+			continue
+		}
+		var date jwt.NumericDate
+		if err := json.Unmarshal(value, &date); err != nil {
+			fmt.Fprintf(&dates, "  %-9s invalid date %s\n", name, value)
+			continue
+		}
+		fmt.Fprintf(&dates, "  %-9s %s (%s)\n", name, date.UTC().Format(time.RFC3339), relative(date.Time))
+	}
+	if dates.Len() > 0 {
+		fmt.Fprintf(stdout, "\nDates:\n%s", dates.Bytes())
+	}
+	return nil
+}
+
+// indentJSON returns indented JSON or a given value as is if it's not a valid JSON.
+func indentJSON(raw []byte) []byte {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, raw, "", "  "); err != nil {
+		return raw
+	}
+	return buf.Bytes()
+}
+
+// relative formats a distance between now and a given time.
+func relative(t time.Time) string {
+	d := t.Sub(now()).Round(time.Second)
+	if d < 0 {
+		return (-d).String() + " ago"
+	}
+	return "in " + d.String()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cristalhq/jwt/v5"
+)
+
+func runJWKS(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("jwks", stderr)
+	use := fs.String("use", "sig", "public key use for keys without it, empty to omit")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("at least one key file is required")
+	}
+
+	jwks := jwt.JWKS{Keys: []jwt.JWK{}}
+	seen := map[string]string{}
+	for _, path := range fs.Args() {
+		keys, err := loadPublicKeys(path)
+		if err != nil {
+			return err
+		}
+
+		for _, jwk := range keys {
+			if err := setJWKParams(jwk, "", "", ""); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			if jwk.Use == "" {
+				jwk.Use = *use
+			}
+			if other, ok := seen[jwk.KeyID]; ok {
+				return fmt.Errorf("%s: duplicate kid %q, also used in %s", path, jwk.KeyID, other)
+			}
+			seen[jwk.KeyID] = path
+			jwks.Keys = append(jwks.Keys, *jwk)
+		}
+	}
+
+	out, err := json.MarshalIndent(jwks, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(stdout, "%s\n", out)
+	return err
+}
+
+// loadPublicKeys returns public parts of keys from a file.
+func loadPublicKeys(path string) ([]*jwt.JWK, error) {
+	key, err := loadKey(path)
+	if err != nil {
+		return nil, err
+	}
+	if key.jwks != nil {
+		keys := make([]*jwt.JWK, 0, len(key.jwks.Keys))
+		for i := range key.jwks.Keys {
+			keys = append(keys, key.jwks.Keys[i].Public())
+		}
+		return keys, nil
+	}
+
+	jwk, err := key.toJWK()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return []*jwt.JWK{jwk.Public()}, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cristalhq/jwt/v5"
+)
+
+func runKeygen(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("keygen", stderr)
+	alg := fs.String("alg", "", "algorithm to generate a key for")
+	format := fs.String("format", "pem", "output format: pem or jwk")
+	kid := fs.String("kid", "", "JWK key ID, defaults to the key thumbprint")
+	bits := fs.Int("bits", 2048, "RSA key size")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	algorithm := jwt.Algorithm(*alg)
+	switch algorithm {
+	case "":
+		return errors.New("-alg is required")
+	case jwt.HS256, jwt.HS384, jwt.HS512:
+		// secret is printed as text and used as is, raw bytes are inconvenient in files and env vars.
+		secret, err := jwt.GenerateRandomBits(hmacBits[algorithm])
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(stdout, base64.RawURLEncoding.EncodeToString(secret))
+		return err
+	}
+
+	key, err := generateKey(algorithm, *bits)
+	if err != nil {
+		return err
+	}
+	jwk, err := jwt.NewPrivateJWK(key)
+	if err != nil {
+		return err
+	}
+	if *format == "jwk" {
+		if err := setJWKParams(jwk, *kid, algorithm, ""); err != nil {
+			return err
+		}
+	}
+
+	out, err := encodeKey(jwk, *format)
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(out)
+	return err
+}
+
+// hmacBits is a secret size for HMAC algorithms, it equals to a hash size.
+var hmacBits = map[jwt.Algorithm]int{
+	jwt.HS256: 256,
+	jwt.HS384: 384,
+	jwt.HS512: 512,
+}
+
+func generateKey(alg jwt.Algorithm, bits int) (crypto.PrivateKey, error) {
+	switch alg {
+	case jwt.RS256, jwt.RS384, jwt.RS512, jwt.PS256, jwt.PS384, jwt.PS512:
+		if bits < 2048 {
+			return nil, errors.New("RSA key size must be at least 2048 bits")
+		}
+		return rsa.GenerateKey(rand.Reader, bits)
+	case jwt.ES256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case jwt.ES384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case jwt.ES512:
+		return ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	case jwt.EdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	default:
+		return nil, jwt.ErrUnsupportedAlg
+	}
+}
+
+// setJWKParams sets optional JWK parameters, key ID defaults to the key thumbprint.
+func setJWKParams(jwk *jwt.JWK, kid string, alg jwt.Algorithm, use string) error {
+	if kid == "" && jwk.KeyID == "" {
+		thumbprint, err := jwk.Thumbprint()
+		if err != nil {
+			return err
+		}
+		kid = thumbprint
+	}
+	if kid != "" {
+		jwk.KeyID = kid
+	}
+	if alg != "" {
+		jwk.Algorithm = alg
+	}
+	if use != "" {
+		jwk.Use = use
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cristalhq/jwt/v5"
+)
+
+func TestKeygen(t *testing.T) {
+	algs := []jwt.Algorithm{
+		jwt.RS256, jwt.PS384,
+		jwt.ES256, jwt.ES384, jwt.ES512,
+		jwt.EdDSA,
+		jwt.HS256, jwt.HS512,
+	}
+
+	for _, alg := range algs {
+		dir := t.TempDir()
+		key := writeFile(t, dir, "key", runOK(t, "", "keygen", "-alg", string(alg)))
+
+		token := runOK(t, `{"sub":"user"}`, "sign", "-alg", string(alg), "-key", key)
+		runOK(t, "", "verify", "-key", key, token)
+
+		if strings.HasPrefix(string(alg), "HS") {
+			continue
+		}
+		public := writeFile(t, dir, "public.json", runOK(t, "", "convert", "-public", key))
+		runOK(t, "", "verify", "-key", public, token)
+
+		_, _, code := runCmd(`{"sub":"user"}`, "sign", "-alg", string(alg), "-key", public)
+		if code != exitUsage {
+			t.Errorf("%s: signed with a public key", alg)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"keygen"},
+		{"keygen", "-alg", "none"},
+		{"keygen", "-alg", "RS256", "-bits", "1024"},
+		{"keygen", "-alg", "ES256", "-format", "der"},
+		{"keygen", "-alg", "ES256", "extra"},
+	} {
+		if _, _, code := runCmd("", args...); code != exitUsage {
+			t.Errorf("jwt %v: got exit code %d, want %d", args, code, exitUsage)
+		}
+	}
+}
+
+func TestKeygenJWK(t *testing.T) {
+	out := runOK(t, "", "keygen", "-alg", "ES384", "-format", "jwk")
+
+	var jwk jwt.JWK
+	if err := json.Unmarshal([]byte(out), &jwk); err != nil {
+		t.Fatal(err)
+	}
+	thumbprint, err := jwk.Thumbprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jwk.Algorithm != jwt.ES384 || jwk.Curve != "P-384" || !jwk.IsPrivate() || jwk.KeyID != thumbprint {
+		t.Fatalf("unexpected key: %s", out)
+	}
+	if got := runOK(t, out, "thumbprint"); got != thumbprint {
+		t.Fatalf("got thumbprint %q, want %q", got, thumbprint)
+	}
+
+	out = runOK(t, "", "keygen", "-alg", "EdDSA", "-format", "jwk", "-kid", "ed-1")
+	if err := json.Unmarshal([]byte(out), &jwk); err != nil {
+		t.Fatal(err)
+	}
+	if jwk.KeyID != "ed-1" {
+		t.Fatalf("got kid %q", jwk.KeyID)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	dir := t.TempDir()
+	privatePEM := runOK(t, "", "keygen", "-alg", "ES256")
+
+	privateJWK := runOK(t, privatePEM, "convert", "-kid", "ec-1", "-alg", "ES256", "-use", "sig")
+	var jwk jwt.JWK
+	if err := json.Unmarshal([]byte(privateJWK), &jwk); err != nil {
+		t.Fatal(err)
+	}
+	if !jwk.IsPrivate() || jwk.KeyID != "ec-1" || jwk.Algorithm != jwt.ES256 || jwk.Use != "sig" {
+		t.Fatalf("unexpected key: %s", privateJWK)
+	}
+
+	// JWK is converted to PEM by default and back to the same PEM.
+	if got := runOK(t, privateJWK, "convert"); got != privatePEM {
+		t.Fatalf("got PEM:\n%s\nwant:\n%s", got, privatePEM)
+	}
+
+	publicPEM := runOK(t, privateJWK, "convert", "-public")
+	if !strings.HasPrefix(publicPEM, "-----BEGIN PUBLIC KEY-----") {
+		t.Fatalf("unexpected public key: %s", publicPEM)
+	}
+	if runOK(t, publicPEM, "thumbprint") != runOK(t, privatePEM, "thumbprint") {
+		t.Fatal("public and private thumbprints differ")
+	}
+
+	secret := writeFile(t, dir, "secret", "secret")
+	for _, args := range [][]string{
+		{"convert", secret},
+		{"convert", "-to", "der", secret},
+		{"thumbprint", secret},
+	} {
+		if _, _, code := runCmd("", args...); code != exitUsage {
+			t.Errorf("jwt %v: got exit code %d, want %d", args, code, exitUsage)
+		}
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	dir := t.TempDir()
+	ecKey := writeFile(t, dir, "ec.pem", runOK(t, "", "keygen", "-alg", "ES256"))
+	edKey := writeFile(t, dir, "ed.json", runOK(t, "", "keygen", "-alg", "EdDSA", "-format", "jwk", "-kid", "ed-1"))
+	rsKey := writeFile(t, dir, "rs.pem", runOK(t, runOK(t, "", "keygen", "-alg", "RS256"), "convert", "-to", "pem", "-public"))
+
+	out := runOK(t, "", "jwks", ecKey, edKey, rsKey)
+	jwksFile := filepath.Join(dir, "jwks.json")
+	if err := os.WriteFile(jwksFile, []byte(out), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var jwks jwt.JWKS
+	if err := json.Unmarshal([]byte(out), &jwks); err != nil {
+		t.Fatal(err)
+	}
+	if len(jwks.Keys) != 3 {
+		t.Fatalf("got %d keys", len(jwks.Keys))
+	}
+	for _, jwk := range jwks.Keys {
+		if jwk.IsPrivate() || jwk.KeyID == "" || jwk.Use != "sig" {
+			t.Fatalf("unexpected key: %+v", jwk)
+		}
+	}
+	if jwks.Keys[1].KeyID != "ed-1" || jwks.Keys[0].KeyID != runOK(t, "", "thumbprint", ecKey) {
+		t.Fatalf("unexpected key IDs: %s", out)
+	}
+
+	token := runOK(t, `{"sub":"user"}`, "sign", "-alg", "EdDSA", "-key", edKey)
+	runOK(t, "", "verify", "-key", jwksFile, token)
+
+	for _, args := range [][]string{
+		{"jwks"},
+		{"jwks", ecKey, ecKey},
+		{"jwks", writeFile(t, dir, "secret", "secret")},
+	} {
+		if _, _, code := runCmd("", args...); code != exitUsage {
+			t.Errorf("jwt %v: got exit code %d, want %d", args, code, exitUsage)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/cristalhq/jwt/v5"
+)
+
+// keyFile is a key loaded from a file, exactly one field is set.
+type keyFile struct {
+	secret  []byte
+	private crypto.PrivateKey
+	public  crypto.PublicKey
+	jwk     *jwt.JWK
+	jwks    *jwt.JWKS
+}
+
+// loadKey reads a PEM, JWK or JWKS file, any other content is treated as an HMAC secret.
+func loadKey(path string) (*keyFile, error) {
+	if path == "" {
+		return nil, errors.New("-key is required")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parseKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// parseKey decodes a PEM, JWK or JWKS, any other content is treated as an HMAC secret.
+func parseKey(data []byte) (*keyFile, error) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("-----BEGIN ")):
+		return parsePEM(trimmed)
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return parseJSONKey(trimmed)
+	default:
+		secret := bytes.TrimRight(data, "\r\n")
+		if len(secret) == 0 {
+			return nil, errors.New("secret is empty")
+		}
+		return &keyFile{secret: secret}, nil
+	}
+}
+
+func parsePEM(data []byte) (*keyFile, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("no supported PEM block found")
+		}
+
+		switch block.Type {
+		case "PRIVATE KEY":
+			key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			return &keyFile{private: key}, nil
+		case "RSA PRIVATE KEY":
+			key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			return &keyFile{private: key}, nil
+		case "EC PRIVATE KEY":
+			key, err := x509.ParseECPrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			return &keyFile{private: key}, nil
+		case "PUBLIC KEY":
+			key, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			return &keyFile{public: key}, nil
+		case "RSA PUBLIC KEY":
+			key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			return &keyFile{public: key}, nil
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			return &keyFile{public: cert.PublicKey}, nil
+		}
+	}
+}
+
+func parseJSONKey(data []byte) (*keyFile, error) {
+	var probe struct {
+		Keys json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+	if probe.Keys != nil {
+		jwks, err := jwt.ParseJWKS(data)
+		if err != nil {
+			return nil, err
+		}
+		return &keyFile{jwks: jwks}, nil
+	}
+
+	jwk, err := jwt.ParseJWK(data)
+	if err != nil {
+		return nil, err
+	}
+	return &keyFile{jwk: jwk}, nil
+}
+
+// algorithm returns an algorithm set in a JWK, if any.
+func (k *keyFile) algorithm() jwt.Algorithm {
+	if k.jwk != nil {
+		return k.jwk.Algorithm
+	}
+	return ""
+}
+
+// keyID returns a key ID set in a JWK, if any.
+func (k *keyFile) keyID() string {
+	if k.jwk != nil {
+		return k.jwk.KeyID
+	}
+	return ""
+}
+
+// toJWK returns a JWK for a single asymmetric key, private members are kept.
+func (k *keyFile) toJWK() (*jwt.JWK, error) {
+	switch {
+	case k.jwk != nil:
+		return k.jwk, nil
+	case k.private != nil:
+		return jwt.NewPrivateJWK(k.private)
+	case k.public != nil:
+		return jwt.NewJWK(k.public)
+	default:
+		return nil, errors.New("a single public or private key is required")
+	}
+}
+
+func (k *keyFile) signer(alg jwt.Algorithm) (jwt.Signer, error) {
+	switch {
+	case k.secret != nil:
+		return jwt.NewSignerHS(alg, k.secret)
+	case k.jwk == nil && k.private == nil:
+		return nil, errors.New("signing requires a private key or a secret")
+	}
+	jwk, err := k.toJWK()
+	if err != nil {
+		return nil, err
+	}
+	return jwk.Signer(alg)
+}
+
+func (k *keyFile) verifier(alg jwt.Algorithm) (jwt.Verifier, error) {
+	switch {
+	case k.secret != nil:
+		return jwt.NewVerifierHS(alg, k.secret)
+	case k.jwks != nil && alg == "":
+		return jwt.NewKeySet(k.jwks)
+	case k.jwks != nil:
+		return jwt.NewKeySet(k.jwks, alg)
+	}
+	jwk, err := k.toJWK()
+	if err != nil {
+		return nil, err
+	}
+	return jwk.Verifier(alg)
+}
+
+// encodeKey returns a key in a given format, PKCS #8 and PKIX are used for PEM.
+func encodeKey(jwk *jwt.JWK, format string) ([]byte, error) {
+	switch format {
+	case "jwk":
+		raw, err := json.MarshalIndent(jwk, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(raw, '\n'), nil
+
+	case "pem":
+		if jwk.IsPrivate() {
+			key, err := jwk.PrivateKey()
+			if err != nil {
+				return nil, err
+			}
+			der, err := x509.MarshalPKCS8PrivateKey(key)
+			if err != nil {
+				return nil, err
+			}
+			return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+		}
+		key, err := jwk.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		der, err := x509.MarshalPKIXPublicKey(key)
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+
+	default:
+		return nil, fmt.Errorf("unknown key format %q", format)
+	}
+}
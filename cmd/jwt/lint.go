@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cristalhq/jwt/v5"
+)
+
+func runLint(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("lint", stderr)
+	keyPath := fs.String("key", "", "HMAC secret file to check it's length")
+	maxLifetime := fs.Duration("max-lifetime", 24*time.Hour, "lifetime after which a token is reported")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	opts := []jwt.AuditOption{jwt.WithAuditMaxLifetime(*maxLifetime)}
+	if *keyPath != "" {
+		key, err := loadKey(*keyPath)
+		if err != nil {
+			return err
+		}
+		if key.secret != nil {
+			opts = append(opts, jwt.WithAuditKey(key.secret))
+		}
+	}
+
+	raw, err := readToken(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	token, err := jwt.ParseNoVerify(raw)
+	if err != nil {
+		return errInvalid{err}
+	}
+
+	var failed int
+	for _, f := range jwt.Audit(token, opts...) {
+		fmt.Fprintln(stdout, f)
+		if f.Severity >= jwt.AuditWarning {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errInvalid{fmt.Errorf("%d issues found", failed)}
+	}
+	return nil
+}
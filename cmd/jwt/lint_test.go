@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	weak := writeFile(t, dir, "weak", "short-secret")
+	strong := writeFile(t, dir, "strong", runOK(t, "", "keygen", "-alg", "HS256"))
+
+	good := `{"iss":"issuer","aud":"api","iat":1700000000,"exp":1700003600}`
+	weakToken := runOK(t, good, "sign", "-alg", "HS256", "-key", weak)
+	strongToken := runOK(t, good, "sign", "-alg", "HS256", "-key", strong, "-kid", "../keys/strong")
+	infoToken := runOK(t, `{"aud":"api","iat":1700000000,"exp":1700003600}`, "sign", "-alg", "HS256", "-key", strong)
+
+	testCases := []struct {
+		args []string
+		want string
+		code int
+	}{
+		{[]string{"lint", weakToken}, "", exitOK},
+		{[]string{"lint", "-key", weak, weakToken}, "critical: weak-key", exitInvalid},
+		{[]string{"lint", "-key", strong, strongToken}, "warning: suspicious-kid", exitInvalid},
+		{[]string{"lint", "-max-lifetime", "30m", weakToken}, "warning: long-lifetime", exitInvalid},
+		{[]string{"lint", infoToken}, "info: missing-iss", exitOK},
+		{[]string{"lint", "not-a-token"}, "", exitInvalid},
+		{[]string{"lint", "-key", "missing", weakToken}, "", exitUsage},
+	}
+
+	for _, tc := range testCases {
+		out, stderr, code := runCmd("", tc.args...)
+		if code != tc.code {
+			t.Errorf("jwt %v: got exit code %d, want %d (%s)", tc.args, code, tc.code, stderr)
+		}
+		if !strings.Contains(out, tc.want) {
+			t.Errorf("jwt %v: output doesn't contain %q:\n%s", tc.args, tc.want, out)
+		}
+	}
+}
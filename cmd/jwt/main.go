@@ -0,0 +1,160 @@
+// Command jwt decodes, signs and verifies JSON Web Tokens and manages keys locally.
+//
+// Usage:
+//
+//	jwt decode [token]
+//	jwt sign -alg ES256 -key key.pem [-kid id] [-typ type] [claims.json]
+//	jwt verify -key key.pem|jwks.json [-alg ES256] [-typ at+jwt] [-leeway 30s] [token]
+//	jwt lint [-key secret] [-max-lifetime 24h] [token]
+//	jwt keygen -alg ES256 [-format pem|jwk] [-kid id]
+//	jwt convert [-to pem|jwk] [-public] [-kid id] [key]
+//	jwt thumbprint [key]
+//	jwt jwks [-use sig] key...
+//
+// Tokens, claims and converted keys are read from stdin when an argument is omitted or is "-".
+// Keys are PEM files, JWK or JWKS documents, any other file is used as an HMAC secret.
+//
+// Exit codes: 0 on success, 1 if a token is invalid or has lint warnings, 2 on a usage or input error.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Exit codes.
+const (
+	exitOK      = 0
+	exitInvalid = 1
+	exitUsage   = 2
+)
+
+const usage = `Usage: jwt <command> [flags] [args]
+
+Commands:
+  decode      print token header and claims without verification
+  sign        sign claims and print a token
+  verify      verify token signature and time claims
+  lint        report token weaknesses according to RFC 8725
+  keygen      generate a key for an algorithm
+  convert     convert a key between PEM and JWK
+  thumbprint  print a JWK thumbprint of a key
+  jwks        assemble public keys into a JWK Set
+
+Run 'jwt <command> -h' for command flags.
+`
+
+// now is replaced in tests.
+var now = time.Now
+
+// errInvalid marks errors caused by an invalid token, not by a bad input.
+type errInvalid struct{ err error }
+
+func (e errInvalid) Error() string { return e.err.Error() }
+func (e errInvalid) Unwrap() error { return e.err }
+
+type command func(args []string, stdin io.Reader, stdout, stderr io.Writer) error
+
+var commands = map[string]command{
+	"decode":     runDecode,
+	"sign":       runSign,
+	"verify":     runVerify,
+	"lint":       runLint,
+	"keygen":     runKeygen,
+	"convert":    runConvert,
+	"thumbprint": runThumbprint,
+	"jwks":       runJWKS,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "jwt: unknown command %q\n\n%s", args[0], usage)
+		return exitUsage
+	}
+
+	err := cmd(args[1:], stdin, stdout, stderr)
+	var invalid errInvalid
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, flag.ErrHelp):
+		return exitUsage
+	case errors.As(err, &invalid):
+		fmt.Fprintf(stderr, "jwt %s: %v\n", args[0], err)
+		return exitInvalid
+	default:
+		fmt.Fprintf(stderr, "jwt %s: %v\n", args[0], err)
+		return exitUsage
+	}
+}
+
+// newFlagSet returns a flag set that reports errors instead of exiting.
+func newFlagSet(name string, output io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("jwt "+name, flag.ContinueOnError)
+	fs.SetOutput(output)
+	return fs
+}
+
+// parseFlags parses command flags, errors are already reported by a flag set.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return flag.ErrHelp
+	}
+	return nil
+}
+
+// readInput returns a file content or stdin if name is empty or "-".
+func readInput(args []string, stdin io.Reader) ([]byte, error) {
+	switch len(args) {
+	case 0:
+		return io.ReadAll(stdin)
+	case 1:
+		if args[0] == "-" {
+			return io.ReadAll(stdin)
+		}
+		return os.ReadFile(args[0])
+	default:
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(args[1:], " "))
+	}
+}
+
+// readToken returns a token from an argument or stdin.
+// Surrounding whitespace and `Bearer ` prefix are removed, so a header value can be pasted as is.
+func readToken(args []string, stdin io.Reader) ([]byte, error) {
+	var raw []byte
+	switch {
+	case len(args) > 1:
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(args[1:], " "))
+	case len(args) == 1 && args[0] != "-":
+		raw = []byte(args[0])
+	default:
+		var err error
+		if raw, err = io.ReadAll(stdin); err != nil {
+			return nil, err
+		}
+	}
+
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 7 && strings.EqualFold(string(raw[:7]), "bearer ") {
+		raw = bytes.TrimSpace(raw[7:])
+	}
+	if len(raw) == 0 {
+		return nil, errors.New("token is empty")
+	}
+	return raw, nil
+}
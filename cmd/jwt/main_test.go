@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cristalhq/jwt/v5"
+)
+
+func TestSignVerify(t *testing.T) {
+	dir := t.TempDir()
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := jwt.NewJWK(&ecKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub.KeyID = "ec-1"
+
+	privatePEM := writePEM(t, dir, "private.pem", "EC PRIVATE KEY", must(x509.MarshalECPrivateKey(ecKey)))
+	publicPEM := writePEM(t, dir, "public.pem", "PUBLIC KEY", must(x509.MarshalPKIXPublicKey(&ecKey.PublicKey)))
+	otherPEM := writePEM(t, dir, "other.pem", "PUBLIC KEY", must(x509.MarshalPKIXPublicKey(&otherKey.PublicKey)))
+	jwksFile := writeJSON(t, dir, "jwks.json", jwt.JWKS{Keys: []jwt.JWK{*pub}})
+	secret := writeFile(t, dir, "secret", "super-secret-key-for-hmac-tests\n")
+
+	claims := `{"sub": "user", "exp": 2000000000}`
+	ecToken := runOK(t, claims, "sign", "-alg", "ES256", "-key", privatePEM, "-kid", "ec-1")
+	hsToken := runOK(t, claims, "sign", "-alg", "HS256", "-key", secret)
+
+	testCases := []struct {
+		args []string
+		want int
+	}{
+		{[]string{"verify", "-key", publicPEM, ecToken}, exitOK},
+		{[]string{"verify", "-key", privatePEM, ecToken}, exitOK},
+		{[]string{"verify", "-key", jwksFile, ecToken}, exitOK},
+		{[]string{"verify", "-key", jwksFile, "-alg", "ES384", ecToken}, exitUsage},
+		{[]string{"verify", "-key", otherPEM, ecToken}, exitInvalid},
+		{[]string{"verify", "-key", publicPEM, "-alg", "ES384", ecToken}, exitUsage},
+		{[]string{"verify", "-key", secret, "-alg", "HS512", hsToken}, exitInvalid},
+		{[]string{"verify", "-key", publicPEM, hsToken}, exitUsage},
+		{[]string{"verify", "-key", secret, hsToken}, exitOK},
+		{[]string{"verify", "-key", secret, "-typ", "at+jwt", hsToken}, exitInvalid},
+		{[]string{"verify", "-key", secret, "-typ", "at+jwt,jwt", hsToken}, exitOK},
+		{[]string{"verify", "-key", secret, "-iss", "issuer", hsToken}, exitInvalid},
+		{[]string{"verify", "-key", secret, "-aud", "api", hsToken}, exitInvalid},
+		{[]string{"verify", "-key", secret, ecToken}, exitUsage},
+		{[]string{"verify", "-key", secret, "not-a-token"}, exitInvalid},
+		{[]string{"verify", hsToken}, exitUsage},
+		{[]string{"sign", "-key", privatePEM}, exitUsage},
+		{[]string{"sign", "-alg", "ES256", "-key", publicPEM}, exitUsage},
+		{[]string{"sign", "-alg", "ES256", "-key", filepath.Join(dir, "missing")}, exitUsage},
+		{[]string{"sign", "-unknown"}, exitUsage},
+		{[]string{"unknown"}, exitUsage},
+		{nil, exitUsage},
+	}
+
+	for _, tc := range testCases {
+		_, stderr, code := runCmd(claims, tc.args...)
+		if code != tc.want {
+			t.Errorf("jwt %v: got exit code %d, want %d (%s)", tc.args, code, tc.want, stderr)
+		}
+	}
+}
+
+func TestVerifyTime(t *testing.T) {
+	secret := writeFile(t, t.TempDir(), "secret", "super-secret-key-for-hmac-tests")
+	token := runOK(t, `{"nbf": 1000, "exp": 2000}`, "sign", "-alg", "HS512", "-key", secret)
+
+	testCases := []struct {
+		now    int64
+		leeway string
+		want   int
+	}{
+		{1500, "0s", exitOK},
+		{999, "0s", exitInvalid},
+		{999, "5s", exitOK},
+		{2000, "0s", exitInvalid},
+		{2003, "5s", exitOK},
+		{2010, "5s", exitInvalid},
+	}
+
+	for _, tc := range testCases {
+		setNow(t, time.Unix(tc.now, 0))
+
+		_, stderr, code := runCmd("", "verify", "-key", secret, "-leeway", tc.leeway, token)
+		if code != tc.want {
+			t.Errorf("now %d: got exit code %d, want %d (%s)", tc.now, code, tc.want, stderr)
+		}
+	}
+}
+
+func TestSignJWK(t *testing.T) {
+	pub, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwk := must(jwt.NewJWK(pub))
+	jwk.KeyID, jwk.Algorithm = "ed-1", jwt.EdDSA
+	jwk.D = base64.RawURLEncoding.EncodeToString(private.Seed())
+
+	dir := t.TempDir()
+	privateFile := writeJSON(t, dir, "private.json", jwk)
+	claimsFile := writeFile(t, dir, "claims.json", `{"sub":"user"}`)
+
+	raw := runOK(t, "", "sign", "-key", privateFile, "-typ", "at+jwt", claimsFile)
+	token, err := jwt.Parse([]byte(raw), must(jwt.NewVerifierEdDSA(pub)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := jwt.Header{Algorithm: jwt.EdDSA, Type: "at+jwt", KeyID: "ed-1"}
+	if token.Header() != want {
+		t.Fatalf("got header %+v, want %+v", token.Header(), want)
+	}
+	if string(token.Claims()) != `{"sub":"user"}` {
+		t.Fatalf("got claims %s", token.Claims())
+	}
+}
+
+func TestDecode(t *testing.T) {
+	secret := writeFile(t, t.TempDir(), "secret", "super-secret-key-for-hmac-tests")
+	token := runOK(t, `{"sub":"user","iat":1700000000,"exp":1700003600}`, "sign", "-alg", "HS256", "-key", secret)
+	setNow(t, time.Unix(1700001800, 0))
+
+	out := runOK(t, "Bearer "+token+"\n", "decode")
+	for _, want := range []string{
+		`"alg": "HS256"`,
+		`"sub": "user"`,
+		"iat       2023-11-14T22:13:20Z (30m0s ago)",
+		"exp       2023-11-14T23:13:20Z (in 30m0s)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output doesn't contain %q:\n%s", want, out)
+		}
+	}
+
+	out = runOK(t, "", "decode", "-json", token)
+	var decoded struct {
+		Header map[string]any `json:"header"`
+		Claims map[string]any `json:"claims"`
+	}
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Header["alg"] != "HS256" || decoded.Claims["sub"] != "user" {
+		t.Fatalf("unexpected output: %s", out)
+	}
+
+	_, _, code := runCmd("", "decode", "eyJhbGciOi.bad")
+	if code != exitInvalid {
+		t.Fatalf("got exit code %d, want %d", code, exitInvalid)
+	}
+}
+
+func runCmd(stdin string, args ...string) (stdout, stderr string, code int) {
+	var out, errOut bytes.Buffer
+	code = run(args, strings.NewReader(stdin), &out, &errOut)
+	return out.String(), errOut.String(), code
+}
+
+func runOK(t *testing.T, stdin string, args ...string) string {
+	t.Helper()
+	out, stderr, code := runCmd(stdin, args...)
+	if code != exitOK {
+		t.Fatalf("jwt %v: exit code %d: %s", args, code, stderr)
+	}
+	return strings.TrimSpace(out)
+}
+
+func setNow(t *testing.T, at time.Time) {
+	t.Cleanup(func() { now = time.Now })
+	now = func() time.Time { return at }
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func writePEM(t *testing.T, dir, name, typ string, der []byte) string {
+	t.Helper()
+	return writeFile(t, dir, name, string(pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})))
+}
+
+func writeJSON(t *testing.T, dir, name string, v any) string {
+	t.Helper()
+	raw, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writeFile(t, dir, name, string(raw))
+}
+
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
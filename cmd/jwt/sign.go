@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cristalhq/jwt/v5"
+)
+
+func runSign(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("sign", stderr)
+	alg := fs.String("alg", "", "signing algorithm, defaults to JWK `alg`")
+	keyPath := fs.String("key", "", "PEM, JWK or secret file")
+	kid := fs.String("kid", "", "key ID header, defaults to JWK `kid`")
+	typ := fs.String("typ", "", "type header (default JWT)")
+	cty := fs.String("cty", "", "content type header")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	key, err := loadKey(*keyPath)
+	if err != nil {
+		return err
+	}
+	algorithm := jwt.Algorithm(*alg)
+	if algorithm == "" {
+		algorithm = key.algorithm()
+	}
+	if algorithm == "" {
+		return errors.New("-alg is required")
+	}
+	signer, err := key.signer(algorithm)
+	if err != nil {
+		return err
+	}
+
+	raw, err := readInput(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	var claims bytes.Buffer
+	if err := json.Compact(&claims, raw); err != nil {
+		return fmt.Errorf("claims are not a valid JSON: %w", err)
+	}
+
+	var opts []jwt.BuilderOption
+	if *kid == "" {
+		*kid = key.keyID()
+	}
+	if *kid != "" {
+		opts = append(opts, jwt.WithKeyID(*kid))
+	}
+	if *typ != "" {
+		opts = append(opts, jwt.WithType(*typ))
+	}
+	if *cty != "" {
+		opts = append(opts, jwt.WithContentType(*cty))
+	}
+
+	token, err := jwt.NewBuilder(signer, opts...).Build(claims.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout, token)
+	return err
+}
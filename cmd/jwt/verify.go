@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cristalhq/jwt/v5"
+)
+
+func runVerify(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("verify", stderr)
+	alg := fs.String("alg", "", "expected algorithm, defaults to token `alg` header")
+	keyPath := fs.String("key", "", "PEM, JWK, JWKS or secret file")
+	leeway := fs.Duration("leeway", 0, "allowed clock skew for exp and nbf")
+	iss := fs.String("iss", "", "expected issuer")
+	aud := fs.String("aud", "", "expected audience")
+	typ := fs.String("typ", "", "comma separated allowed `typ` values, empty value allows untyped tokens")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	key, err := loadKey(*keyPath)
+	if err != nil {
+		return err
+	}
+	raw, err := readToken(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	var opts []jwt.ParseOption
+	if *typ != "" {
+		opts = append(opts, jwt.WithAllowedTypes(strings.Split(*typ, ",")...))
+	}
+	token, err := jwt.ParseNoVerify(raw, opts...)
+	if err != nil {
+		return errInvalid{err}
+	}
+
+	algorithm := jwt.Algorithm(*alg)
+	if algorithm == "" && key.jwks == nil {
+		algorithm = token.Header().Algorithm
+	}
+	verifier, err := key.verifier(algorithm)
+	if err != nil {
+		return err
+	}
+	if err := verifier.Verify(token); err != nil {
+		return errInvalid{err}
+	}
+
+	var claims jwt.RegisteredClaims
+	if err := token.DecodeRegisteredClaims(&claims); err != nil {
+		return errInvalid{err}
+	}
+	if err := checkClaims(&claims, now(), *leeway, *iss, *aud); err != nil {
+		return errInvalid{err}
+	}
+
+	_, err = fmt.Fprintln(stdout, "token is valid")
+	return err
+}
+
+func checkClaims(claims *jwt.RegisteredClaims, now time.Time, leeway time.Duration, iss, aud string) error {
+	switch {
+	case !claims.IsValidExpiresAt(now.Add(-leeway)):
+		return jwt.ErrTokenExpired
+	case !claims.IsValidNotBefore(now.Add(leeway)):
+		return jwt.ErrTokenNotValidYet
+	case iss != "" && !claims.IsIssuer(iss):
+		return jwt.ErrIssuerMismatch
+	case aud != "" && !claims.IsForAudience(aud):
+		return jwt.ErrAudienceMismatch
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DPoPClaims represents claims of DPoP proof.
+// See: https://datatracker.ietf.org/doc/html/rfc9449#section-4.2
+type DPoPClaims struct {
+	// ID claim is a unique identifier of the proof.
+	ID string `json:"jti"`
+
+	// Method claim is an HTTP method of the request.
+	Method string `json:"htm"`
+
+	// URL claim is an HTTP URL of the request without query and fragment.
+	URL string `json:"htu"`
+
+	// IssuedAt claim is the proof creation time.
+	IssuedAt *NumericDate `json:"iat"`
+
+	// AccessTokenHash claim is a hash of the access token, see DPoPAccessTokenHash.
+	AccessTokenHash string `json:"ath,omitempty"`
+
+	// Nonce claim is a value provided by the server in `DPoP-Nonce` header.
+	Nonce string `json:"nonce,omitempty"`
+}
+
+// DPoPAccessTokenHash returns a value of `ath` claim for a given access token.
+func DPoPAccessTokenHash(accessToken string) string {
+	digest := sha256.Sum256([]byte(accessToken))
+	return b64EncodeString(digest[:])
+}
+
+// DPoPProver creates DPoP proofs, used by clients.
+// Safe to use concurrently.
+type DPoPProver struct {
+	builder    *Builder
+	thumbprint string
+	now        func() time.Time
+}
+
+// NewDPoPProver returns new instance of DPoPProver.
+// Public key must be a pair of the signer's private key and is embedded into every proof.
+// Private keys and keys which don't verify signer's signatures are rejected with ErrInvalidKey.
+func NewDPoPProver(signer Signer, key crypto.PublicKey) (*DPoPProver, error) {
+	if _, ok := signer.(*HSAlg); ok {
+		return nil, ErrUnsupportedAlg
+	}
+	jwk, err := NewJWK(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkKeyPair(signer, jwk); err != nil {
+		return nil, err
+	}
+	thumbprint, err := jwk.Thumbprint()
+	if err != nil {
+		return nil, err
+	}
+	return &DPoPProver{
+		builder:    NewBuilder(signer, WithType(TypeDPoP), WithJWK(jwk)),
+		thumbprint: thumbprint,
+		now:        time.Now,
+	}, nil
+}
+
+// checkKeyPair signs a probe token, so a mismatched key fails here and not on every proof.
+func checkKeyPair(signer Signer, jwk *JWK) error {
+	verifier, err := jwk.Verifier(signer.Algorithm())
+	if err != nil {
+		return err
+	}
+	probe, err := NewBuilder(signer).Build([]byte(`{}`))
+	if err != nil {
+		return err
+	}
+	if err := verifier.Verify(probe); err != nil {
+		return ErrInvalidKey
+	}
+	return nil
+}
+
+// Thumbprint returns JWK Thumbprint of the prover key, used as `dpop_jkt` and `cnf.jkt` values.
+func (p *DPoPProver) Thumbprint() string {
+	return p.thumbprint
+}
+
+// Proof creates a DPoP proof for a request.
+// Access token and nonce are optional.
+func (p *DPoPProver) Proof(method, url, accessToken, nonce string) (*Token, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &DPoPClaims{
+		ID:       jti,
+		Method:   method,
+		URL:      stripQuery(url),
+		IssuedAt: NewNumericDate(p.now()),
+		Nonce:    nonce,
+	}
+	if accessToken != "" {
+		claims.AccessTokenHash = DPoPAccessTokenHash(accessToken)
+	}
+	return p.builder.Build(claims)
+}
+
+// DPoPRequest describes a request to check DPoP proof against.
+type DPoPRequest struct {
+	// Method is an HTTP method of the request.
+	Method string
+
+	// URL is an HTTP URL of the request.
+	URL string
+
+	// AccessToken presented with the proof, checked against `ath` claim if not empty.
+	AccessToken string
+
+	// JKT is `cnf.jkt` claim of the access token, checked against proof key thumbprint if not empty.
+	JKT string
+
+	// Nonce is an expected `nonce` claim, not checked if empty.
+	Nonce string
+}
+
+// DPoPProof is a verified DPoP proof.
+type DPoPProof struct {
+	Token      *Token
+	Claims     *DPoPClaims
+	Thumbprint string
+}
+
+// DPoPValidator verifies DPoP proofs, used by servers.
+// See: https://datatracker.ietf.org/doc/html/rfc9449#section-4.3
+type DPoPValidator struct {
+	// Algorithms allowed for proofs, all asymmetric algorithms if empty.
+	Algorithms []Algorithm
+
+	// MaxAge is a max allowed age of the proof, default is 1 minute.
+	MaxAge time.Duration
+
+	// Leeway is an allowed clock skew for `iat` claim.
+	Leeway time.Duration
+
+	// Replay rejects proofs with an already seen `jti`, not checked if nil.
+	Replay *ReplayGuard
+
+	// Now returns current time, time.Now is used if nil.
+	Now func() time.Time
+}
+
+// Parse decodes a DPoP proof, verifies it's signature with the embedded key and checks it against a request.
+func (v *DPoPValidator) Parse(raw []byte, req DPoPRequest) (*DPoPProof, error) {
+	token, err := ParseNoVerify(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	header := token.Header()
+	switch {
+	case !hasType(header, TypeDPoP):
+		return nil, ErrTypeMismatch
+	case header.JWK == nil:
+		return nil, ErrKeyNotFound
+	case header.JWK.IsPrivate():
+		return nil, ErrInvalidKey
+	case !v.isAllowed(header.Algorithm):
+		return nil, ErrUnsupportedAlg
+	}
+
+	verifier, err := header.JWK.Verifier(header.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifier.Verify(token); err != nil {
+		return nil, err
+	}
+
+	thumbprint, err := header.JWK.Thumbprint()
+	if err != nil {
+		return nil, err
+	}
+	if req.JKT != "" && !constTimeEqual(thumbprint, req.JKT) {
+		return nil, ErrProofMismatch
+	}
+
+	var claims DPoPClaims
+	if err := token.DecodeClaims(&claims); err != nil {
+		return nil, err
+	}
+
+	maxAge := v.MaxAge
+	if maxAge == 0 {
+		maxAge = time.Minute
+	}
+	now := nowFunc(v.Now)
+
+	var errs claimErrors
+	errs.check(claims.ID != "", "jti", ErrMissingID)
+	errs.require(claims.IssuedAt != nil, "iat")
+	errs.check(claims.Method == req.Method, "htm", ErrProofMismatch)
+	errs.check(sameHTU(claims.URL, req.URL), "htu", ErrProofMismatch)
+	errs.check(req.Nonce == "" || constTimeEqual(claims.Nonce, req.Nonce), "nonce", ErrNonceMismatch)
+	errs.check(req.AccessToken == "" || constTimeEqual(claims.AccessTokenHash, DPoPAccessTokenHash(req.AccessToken)), "ath", ErrProofMismatch)
+	errs.check(claims.IssuedAt == nil || !claims.IssuedAt.After(now.Add(v.Leeway)), "iat", ErrTokenNotValidYet)
+	errs.checkAge(claims.IssuedAt, now, maxAge, v.Leeway)
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	if v.Replay != nil {
+		if err := v.Replay.Use(claims.ID, claims.IssuedAt.Add(maxAge+v.Leeway)); err != nil {
+			return nil, err
+		}
+	}
+
+	proof := &DPoPProof{
+		Token:      token,
+		Claims:     &claims,
+		Thumbprint: thumbprint,
+	}
+	return proof, nil
+}
+
+func (v *DPoPValidator) isAllowed(alg Algorithm) bool {
+	if len(v.Algorithms) > 0 {
+		return containsAlg(v.Algorithms, alg)
+	}
+	switch alg {
+	case "", HS256, HS384, HS512:
+		return false
+	default:
+		_, err := hashOf(alg)
+		return err == nil
+	}
+}
+
+// sameHTU reports whether URLs are equal ignoring query and fragment.
+// See: https://datatracker.ietf.org/doc/html/rfc9449#section-4.3
+func sameHTU(a, b string) bool {
+	ua, errA := url.Parse(a)
+	ub, errB := url.Parse(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return strings.EqualFold(ua.Scheme, ub.Scheme) &&
+		strings.EqualFold(hostPort(ua), hostPort(ub)) &&
+		escapedPath(ua) == escapedPath(ub)
+}
+
+func escapedPath(u *url.URL) string {
+	if p := u.EscapedPath(); p != "" {
+		return p
+	}
+	return "/"
+}
+
+func hostPort(u *url.URL) string {
+	port := u.Port()
+	if port == "" || (port == "443" && strings.EqualFold(u.Scheme, "https")) ||
+		(port == "80" && strings.EqualFold(u.Scheme, "http")) {
+		return u.Hostname()
+	}
+	return u.Hostname() + ":" + port
+}
+
+func stripQuery(s string) string {
+	if i := strings.IndexAny(s, "?#"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
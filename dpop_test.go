@@ -0,0 +1,225 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDPoP(t *testing.T) {
+	prover, err := NewDPoPProver(must(NewSignerES(ES256, ecdsaPrivateKey256)), ecdsaPublicKey256)
+	mustOk(t, err)
+
+	proof, err := prover.Proof("POST", "https://rs.example.com/resource?x=1#frag", "access-token", "server-nonce")
+	mustOk(t, err)
+	mustEqual(t, proof.Header().Type, "dpop+jwt")
+	mustEqual(t, proof.Header().JWK, must(NewJWK(ecdsaPublicKey256)))
+
+	validator := &DPoPValidator{Replay: NewReplayGuard(100, 0)}
+	req := DPoPRequest{
+		Method:      "POST",
+		URL:         "https://RS.example.com:443/resource?y=2",
+		AccessToken: "access-token",
+		JKT:         prover.Thumbprint(),
+		Nonce:       "server-nonce",
+	}
+
+	verified, err := validator.Parse(proof.Bytes(), req)
+	mustOk(t, err)
+	mustEqual(t, verified.Thumbprint, prover.Thumbprint())
+	mustEqual(t, verified.Claims.URL, "https://rs.example.com/resource")
+
+	_, err = validator.Parse(proof.Bytes(), req)
+	mustEqual(t, err, ErrTokenReplayed)
+}
+
+func TestDPoPValidator(t *testing.T) {
+	prover := must(NewDPoPProver(must(NewSignerEdDSA(ed25519PrivateKey)), ed25519PublicKey))
+	another := must(NewDPoPProver(must(NewSignerES(ES256, ecdsaPrivateKey256)), ecdsaPublicKey256))
+
+	validator := &DPoPValidator{}
+	req := DPoPRequest{
+		Method:      "GET",
+		URL:         "https://rs.example.com/resource",
+		AccessToken: "access-token",
+		JKT:         prover.Thumbprint(),
+		Nonce:       "nonce",
+	}
+
+	testCases := []struct {
+		proof   func() (*Token, error)
+		req     func(r *DPoPRequest)
+		wantErr error
+	}{
+		{
+			func() (*Token, error) { return prover.Proof("GET", req.URL, "access-token", "nonce") },
+			func(r *DPoPRequest) {},
+			nil,
+		},
+		{
+			func() (*Token, error) { return prover.Proof("POST", req.URL, "access-token", "nonce") },
+			func(r *DPoPRequest) {},
+			ErrProofMismatch,
+		},
+		{
+			func() (*Token, error) {
+				return prover.Proof("GET", "https://rs.example.com/other", "access-token", "nonce")
+			},
+			func(r *DPoPRequest) {},
+			ErrProofMismatch,
+		},
+		{
+			func() (*Token, error) { return prover.Proof("GET", req.URL, "another-token", "nonce") },
+			func(r *DPoPRequest) {},
+			ErrProofMismatch,
+		},
+		{
+			func() (*Token, error) { return prover.Proof("GET", req.URL, "access-token", "old-nonce") },
+			func(r *DPoPRequest) {},
+			ErrNonceMismatch,
+		},
+		{
+			func() (*Token, error) { return another.Proof("GET", req.URL, "access-token", "nonce") },
+			func(r *DPoPRequest) {},
+			ErrProofMismatch,
+		},
+		{
+			func() (*Token, error) { return another.Proof("GET", req.URL, "", "") },
+			func(r *DPoPRequest) { r.AccessToken, r.JKT, r.Nonce = "", "", "" },
+			nil,
+		},
+		{
+			func() (*Token, error) {
+				p := must(NewDPoPProver(must(NewSignerEdDSA(ed25519PrivateKey)), ed25519PublicKey))
+				p.now = func() time.Time { return time.Now().Add(-time.Hour) }
+				return p.Proof("GET", req.URL, "access-token", "nonce")
+			},
+			func(r *DPoPRequest) {},
+			ErrTokenExpired,
+		},
+		{
+			func() (*Token, error) {
+				p := must(NewDPoPProver(must(NewSignerEdDSA(ed25519PrivateKey)), ed25519PublicKey))
+				p.now = func() time.Time { return time.Now().Add(time.Hour) }
+				return p.Proof("GET", req.URL, "access-token", "nonce")
+			},
+			func(r *DPoPRequest) {},
+			ErrTokenNotValidYet,
+		},
+		{
+			func() (*Token, error) {
+				return NewBuilder(must(NewSignerEdDSA(ed25519PrivateKey)), WithJWK(must(NewJWK(ed25519PublicKey)))).
+					Build(&DPoPClaims{ID: "id", Method: "GET", URL: req.URL, IssuedAt: NewNumericDate(time.Now())})
+			},
+			func(r *DPoPRequest) { r.AccessToken, r.Nonce = "", "" },
+			ErrTypeMismatch,
+		},
+		{
+			func() (*Token, error) {
+				return NewBuilder(must(NewSignerEdDSA(ed25519PrivateKey)), WithType(TypeDPoP)).
+					Build(&DPoPClaims{ID: "id", Method: "GET", URL: req.URL, IssuedAt: NewNumericDate(time.Now())})
+			},
+			func(r *DPoPRequest) { r.AccessToken, r.Nonce = "", "" },
+			ErrKeyNotFound,
+		},
+		{
+			func() (*Token, error) {
+				return NewBuilder(must(NewSignerEdDSA(ed25519PrivateKeyAnother)), WithType(TypeDPoP), WithJWK(must(NewJWK(ed25519PublicKey)))).
+					Build(&DPoPClaims{ID: "id", Method: "GET", URL: req.URL, IssuedAt: NewNumericDate(time.Now())})
+			},
+			func(r *DPoPRequest) { r.AccessToken, r.Nonce = "", "" },
+			ErrInvalidSignature,
+		},
+		{
+			func() (*Token, error) {
+				return NewBuilder(must(NewSignerHS(HS256, hsKey256)), WithType(TypeDPoP), WithJWK(must(NewJWK(ed25519PublicKey)))).
+					Build(&DPoPClaims{ID: "id", Method: "GET", URL: req.URL, IssuedAt: NewNumericDate(time.Now())})
+			},
+			func(r *DPoPRequest) { r.AccessToken, r.Nonce = "", "" },
+			ErrUnsupportedAlg,
+		},
+		{
+			func() (*Token, error) {
+				return NewBuilder(must(NewSignerEdDSA(ed25519PrivateKey)), WithType(TypeDPoP), WithJWK(must(NewPrivateJWK(ed25519PrivateKey)))).
+					Build(&DPoPClaims{ID: "id", Method: "GET", URL: req.URL, IssuedAt: NewNumericDate(time.Now())})
+			},
+			func(r *DPoPRequest) { r.AccessToken, r.Nonce = "", "" },
+			ErrInvalidKey,
+		},
+	}
+
+	for _, tc := range testCases {
+		proof, err := tc.proof()
+		mustOk(t, err)
+
+		r := req
+		tc.req(&r)
+
+		_, err = validator.Parse(proof.Bytes(), r)
+		mustEqual(t, errors.Is(err, tc.wantErr), true)
+	}
+}
+
+func TestDPoPProverBadKey(t *testing.T) {
+	_, err := NewDPoPProver(must(NewSignerHS(HS256, hsKey256)), ecdsaPublicKey256)
+	mustEqual(t, err, ErrUnsupportedAlg)
+
+	signer := must(NewSignerES(ES256, ecdsaPrivateKey256))
+	_, err = NewDPoPProver(signer, ecdsaPrivateKey256)
+	mustEqual(t, err, ErrInvalidKey)
+
+	_, err = NewDPoPProver(must(NewSignerEdDSA(ed25519PrivateKey)), ed25519PrivateKey)
+	mustEqual(t, err, ErrInvalidKey)
+
+	// key is not a pair of the signer key.
+	_, err = NewDPoPProver(must(NewSignerES(ES256, ecdsaPrivateKey256Another)), ecdsaPublicKey256)
+	mustEqual(t, err, ErrInvalidKey)
+
+	_, err = NewDPoPProver(must(NewSignerEdDSA(ed25519PrivateKeyAnother)), ed25519PublicKey)
+	mustEqual(t, err, ErrInvalidKey)
+
+	// key type doesn't match the signer algorithm.
+	_, err = NewDPoPProver(signer, ed25519PublicKey)
+	mustFail(t, err)
+}
+
+func TestJWKThumbprint(t *testing.T) {
+	for _, key := range []any{rsaPublicKey256, ecdsaPublicKey384, ed25519PublicKey} {
+		jwk := must(NewJWK(key))
+		jwk.KeyID, jwk.Use = "ignored", "sig"
+
+		// encoding/json sorts map keys, so it's a canonical form.
+		members := map[string]string{"kty": jwk.KeyType}
+		switch jwk.KeyType {
+		case "RSA":
+			members["n"], members["e"] = jwk.N, jwk.E
+		case "EC":
+			members["crv"], members["x"], members["y"] = jwk.Curve, jwk.X, jwk.Y
+		case "OKP":
+			members["crv"], members["x"] = jwk.Curve, jwk.X
+		}
+		want := DPoPAccessTokenHash(string(must(json.Marshal(members))))
+
+		mustEqual(t, must(jwk.Thumbprint()), want)
+	}
+}
+
+func TestSameHTU(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		want bool
+	}{
+		{"https://example.com/path", "https://example.com/path", true},
+		{"https://example.com/path", "https://EXAMPLE.com:443/path?q=1#f", true},
+		{"https://example.com", "https://example.com/", true},
+		{"http://example.com:80/", "http://example.com/", true},
+		{"https://example.com/path", "http://example.com/path", false},
+		{"https://example.com:8443/path", "https://example.com/path", false},
+		{"https://example.com/path", "https://example.com/Path", false},
+	}
+
+	for _, tc := range testCases {
+		mustEqual(t, sameHTU(tc.a, tc.b), tc.want)
+	}
+}
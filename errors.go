@@ -34,4 +34,168 @@ var (
 
 	// ErrUninitializedToken indicates that token was not create with Parse func.
 	ErrUninitializedToken = errors.New("token was not initialized")
+
+	// ErrTokenNotFound indicates that token is not present in a request.
+	ErrTokenNotFound = errors.New("token not found")
+
+	// ErrMissingID indicates that token has no `jti` claim.
+	ErrMissingID = errors.New("token id is missing")
+
+	// ErrTokenRevoked indicates that token was revoked.
+	ErrTokenRevoked = errors.New("token is revoked")
+
+	// ErrMissingExpiresAt indicates that token has no `exp` claim.
+	ErrMissingExpiresAt = errors.New("token expiration is missing")
+
+	// ErrTokenExpired indicates that token is expired.
+	ErrTokenExpired = errors.New("token is expired")
+
+	// ErrLifetimeTooLong indicates that token expires later than allowed.
+	ErrLifetimeTooLong = errors.New("token lifetime is too long")
+
+	// ErrTokenReplayed indicates that token was already used.
+	ErrTokenReplayed = errors.New("token is already used")
+
+	// ErrReplayCacheFull indicates that replay guard cannot remember more tokens.
+	ErrReplayCacheFull = errors.New("replay cache is full")
+
+	// ErrMissingClaim indicates that a required claim is missing.
+	ErrMissingClaim = errors.New("required claim is missing")
+
+	// ErrTypeMismatch indicates that token `typ` header is not expected.
+	ErrTypeMismatch = errors.New("token type is not expected")
+
+	// ErrIssuerMismatch indicates that token issuer is not expected.
+	ErrIssuerMismatch = errors.New("token issuer is not expected")
+
+	// ErrAudienceMismatch indicates that token is not intended for the audience.
+	ErrAudienceMismatch = errors.New("token audience is not expected")
+
+	// ErrClientMismatch indicates that token client is not expected.
+	ErrClientMismatch = errors.New("token client is not expected")
+
+	// ErrTokenNotValidYet indicates that token is used before `nbf` or `iat` claims.
+	ErrTokenNotValidYet = errors.New("token is not valid yet")
+
+	// ErrNonceMismatch indicates that token `nonce` claim is not expected.
+	ErrNonceMismatch = errors.New("token nonce is not expected")
+
+	// ErrAuthorizedPartyMismatch indicates that token `azp` claim is not expected.
+	ErrAuthorizedPartyMismatch = errors.New("token authorized party is not expected")
+
+	// ErrAuthTimeTooOld indicates that end-user authentication is older than allowed.
+	ErrAuthTimeTooOld = errors.New("authentication time is too old")
+
+	// ErrHashMismatch indicates that `at_hash` or `c_hash` claim doesn't match a given value.
+	ErrHashMismatch = errors.New("token hash claim doesn't match")
+
+	// ErrProofMismatch indicates that proof doesn't match the request or the access token.
+	ErrProofMismatch = errors.New("proof doesn't match the request")
+
+	// ErrInvalidDisclosure indicates that SD-JWT disclosure is not valid.
+	ErrInvalidDisclosure = errors.New("disclosure is not valid")
+
+	// ErrKeyNotFound indicates that there is no key to verify the token.
+	ErrKeyNotFound = errors.New("key not found")
+
+	// ErrInvalidMetadata indicates that provider metadata is not valid.
+	ErrInvalidMetadata = errors.New("provider metadata is not valid")
+
+	// ErrInvalidConfig indicates that config is not valid.
+	ErrInvalidConfig = errors.New("config is not valid")
+
+	// ErrRefreshTokenNotFound indicates that refresh token is unknown.
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+
+	// ErrRefreshTokenReused indicates that refresh token was already used and it's family is revoked.
+	ErrRefreshTokenReused = errors.New("refresh token is reused")
+
+	// ErrRefreshTokenExists indicates that refresh token with the same id is already stored.
+	ErrRefreshTokenExists = errors.New("refresh token already exists")
+
+	// ErrUnexpectedClaim indicates that token has a claim which is not allowed.
+	ErrUnexpectedClaim = errors.New("claim is not allowed")
+
+	// ErrInsufficientScope indicates that token scope doesn't allow the request.
+	ErrInsufficientScope = errors.New("token scope is insufficient")
+
+	// ErrInvalidClaims indicates that claims are not a JSON object.
+	ErrInvalidClaims = errors.New("claims must be a JSON object")
 )
+
+// ParseError describes why a token cannot be decoded.
+// It matches ErrInvalidFormat with errors.Is.
+type ParseError struct {
+	Segment string // "header", "claims", "signature" or empty if token structure is not valid
+	Err     error  // underlying base64 or JSON error
+}
+
+func (e *ParseError) Error() string {
+	msg := ErrInvalidFormat.Error()
+	if e.Segment != "" {
+		msg += ": " + e.Segment
+	}
+	if e.Err == nil {
+		return msg
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// Is reports whether target is ErrInvalidFormat.
+func (e *ParseError) Is(target error) bool { return target == ErrInvalidFormat }
+
+// ClaimError describes a single failed claim.
+type ClaimError struct {
+	Claim string // claim name, like "exp"
+	Err   error  // reason, like ErrTokenExpired
+}
+
+func (e *ClaimError) Error() string {
+	if e.Err == nil {
+		return e.Claim
+	}
+	return e.Claim + ": " + e.Err.Error()
+}
+
+// Unwrap returns the reason.
+func (e *ClaimError) Unwrap() error { return e.Err }
+
+// ValidationError lists every failed claim of a token.
+// errors.Is and errors.As match errors of any failed claim.
+type ValidationError struct {
+	Claims []*ClaimError
+}
+
+func (e *ValidationError) Error() string {
+	msg := "token claims are not valid: "
+	for i, c := range e.Claims {
+		if i > 0 {
+			msg += "; "
+		}
+		msg += c.Error()
+	}
+	return msg
+}
+
+// Is reports whether any failed claim matches target.
+func (e *ValidationError) Is(target error) bool {
+	for _, c := range e.Claims {
+		if errors.Is(c, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first failed claim error that matches target.
+func (e *ValidationError) As(target any) bool {
+	for _, c := range e.Claims {
+		if errors.As(c, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidationError(t *testing.T) {
+	err := error(&ValidationError{Claims: []*ClaimError{
+		{Claim: "exp", Err: ErrTokenExpired},
+		{Claim: "aud", Err: ErrAudienceMismatch},
+	}})
+
+	mustEqual(t, err.Error(), "token claims are not valid: exp: token is expired; aud: token audience is not expected")
+	mustEqual(t, errors.Is(err, ErrTokenExpired), true)
+	mustEqual(t, errors.Is(err, ErrAudienceMismatch), true)
+	mustEqual(t, errors.Is(err, ErrIssuerMismatch), false)
+
+	var claimErr *ClaimError
+	mustEqual(t, errors.As(err, &claimErr), true)
+	mustEqual(t, claimErr.Claim, "exp")
+}
+
+func TestErrorsNilErr(t *testing.T) {
+	mustEqual(t, (&ParseError{Segment: "header"}).Error(), "token format is not valid: header")
+	mustEqual(t, (&ParseError{}).Error(), "token format is not valid")
+	mustEqual(t, (&ClaimError{Claim: "exp"}).Error(), "exp")
+}
+
+func TestValidationErrorClaims(t *testing.T) {
+	signer := must(NewSignerHS(HS256, hsKey256))
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+	now := time.Now()
+
+	token := must(NewBuilder(signer, WithType(TypeAccessToken)).Build(&AccessTokenClaims{
+		RegisteredClaims: RegisteredClaims{
+			Issuer:    "https://another.example.com",
+			Audience:  Audience{"api"},
+			ExpiresAt: NewNumericDate(now.Add(-time.Minute)),
+			IssuedAt:  NewNumericDate(now.Add(-time.Hour)),
+		},
+	}))
+
+	validator := &AccessTokenValidator{Issuer: "https://auth.example.com", Audience: "api"}
+	_, err := validator.Parse(token.Bytes(), verifier)
+
+	var validationErr *ValidationError
+	mustEqual(t, errors.As(err, &validationErr), true)
+
+	var have []string
+	for _, c := range validationErr.Claims {
+		have = append(have, c.Error())
+	}
+	mustEqual(t, have, []string{
+		"sub: required claim is missing",
+		"client_id: required claim is missing",
+		"jti: required claim is missing",
+		"iss: token issuer is not expected",
+		"exp: token is expired",
+	})
+	mustEqual(t, errors.Is(err, ErrMissingClaim), true)
+	mustEqual(t, errors.Is(err, ErrTokenExpired), true)
+}
+
+func TestParseErrorDecodeClaims(t *testing.T) {
+	token := must(ParseNoVerify([]byte(bytesToBase64([]byte(`{"alg":"HS256"}`)) + "." + bytesToBase64([]byte(`{"exp":"soon"}`)) + ".c2ln")))
+
+	var claims RegisteredClaims
+	err := token.DecodeClaims(&claims)
+	mustEqual(t, errors.Is(err, ErrInvalidFormat), true)
+	mustEqual(t, errors.Is(err, ErrDateInvalidFormat), true)
+
+	var parseErr *ParseError
+	mustEqual(t, errors.As(err, &parseErr), true)
+	mustEqual(t, parseErr.Segment, "claims")
+}
@@ -0,0 +1,101 @@
+package jwt
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Extractor is used to get a raw token from an HTTP request.
+// If there is no token in a request ErrTokenNotFound must be returned.
+type Extractor interface {
+	Extract(r *http.Request) ([]byte, error)
+}
+
+// ExtractorFunc is an adapter to use an ordinary function as an Extractor.
+type ExtractorFunc func(r *http.Request) ([]byte, error)
+
+// Extract implements Extractor interface.
+func (f ExtractorFunc) Extract(r *http.Request) ([]byte, error) {
+	return f(r)
+}
+
+// ParseRequest extracts a token from a request, decodes it and verifies it's signature.
+func ParseRequest(r *http.Request, extractor Extractor, verifier Verifier, opts ...ParseOption) (*Token, error) {
+	raw, err := extractor.Extract(r)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(raw, verifier, opts...)
+}
+
+// ExtractFromAuthHeader returns an Extractor for `Authorization: Bearer <token>` header.
+func ExtractFromAuthHeader() Extractor {
+	return ExtractFromHeader("Authorization", "Bearer")
+}
+
+// ExtractFromHeader returns an Extractor for a given header and auth scheme.
+// Scheme is compared case-insensitively, empty scheme means that the whole header value is a token.
+func ExtractFromHeader(header, scheme string) Extractor {
+	return ExtractorFunc(func(r *http.Request) ([]byte, error) {
+		value := r.Header.Get(header)
+		if scheme != "" {
+			if len(value) <= len(scheme) || value[len(scheme)] != ' ' ||
+				!strings.EqualFold(value[:len(scheme)], scheme) {
+				return nil, ErrTokenNotFound
+			}
+			value = value[len(scheme)+1:]
+		}
+		return nonEmpty(strings.TrimSpace(value))
+	})
+}
+
+// ExtractFromCookie returns an Extractor for a cookie with a given name.
+func ExtractFromCookie(name string) Extractor {
+	return ExtractorFunc(func(r *http.Request) ([]byte, error) {
+		cookie, err := r.Cookie(name)
+		if err != nil {
+			return nil, ErrTokenNotFound
+		}
+		return nonEmpty(cookie.Value)
+	})
+}
+
+// ExtractFromQuery returns an Extractor for a URL query parameter with a given name.
+func ExtractFromQuery(param string) Extractor {
+	return ExtractorFunc(func(r *http.Request) ([]byte, error) {
+		return nonEmpty(r.URL.Query().Get(param))
+	})
+}
+
+// ExtractFromForm returns an Extractor for a form field (request body only) with a given name.
+func ExtractFromForm(field string) Extractor {
+	return ExtractorFunc(func(r *http.Request) ([]byte, error) {
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+		return nonEmpty(r.PostForm.Get(field))
+	})
+}
+
+// ExtractFirst returns an Extractor that tries given extractors in order.
+// The first found token is returned, other errors are returned immediately.
+func ExtractFirst(extractors ...Extractor) Extractor {
+	return ExtractorFunc(func(r *http.Request) ([]byte, error) {
+		for _, e := range extractors {
+			raw, err := e.Extract(r)
+			if errors.Is(err, ErrTokenNotFound) {
+				continue
+			}
+			return raw, err
+		}
+		return nil, ErrTokenNotFound
+	})
+}
+
+func nonEmpty(s string) ([]byte, error) {
+	if s == "" {
+		return nil, ErrTokenNotFound
+	}
+	return []byte(s), nil
+}
@@ -0,0 +1,145 @@
+package jwt
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	newReq := func(f func(r *http.Request)) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/?access_token=query-token", nil)
+		f(r)
+		return r
+	}
+
+	testCases := []struct {
+		extractor Extractor
+		req       *http.Request
+		want      string
+		wantErr   error
+	}{
+		{
+			ExtractFromAuthHeader(),
+			newReq(func(r *http.Request) { r.Header.Set("Authorization", "Bearer header-token") }),
+			"header-token", nil,
+		},
+		{
+			ExtractFromAuthHeader(),
+			newReq(func(r *http.Request) { r.Header.Set("Authorization", "bearer header-token") }),
+			"header-token", nil,
+		},
+		{
+			ExtractFromAuthHeader(),
+			newReq(func(r *http.Request) { r.Header.Set("Authorization", "Basic dXNlcjpwYXNz") }),
+			"", ErrTokenNotFound,
+		},
+		{
+			ExtractFromAuthHeader(),
+			newReq(func(r *http.Request) { r.Header.Set("Authorization", "Bearer") }),
+			"", ErrTokenNotFound,
+		},
+		{
+			ExtractFromAuthHeader(),
+			newReq(func(r *http.Request) { r.Header.Set("Authorization", "Bearer ") }),
+			"", ErrTokenNotFound,
+		},
+		{
+			ExtractFromHeader("X-Token", ""),
+			newReq(func(r *http.Request) { r.Header.Set("X-Token", "raw-token") }),
+			"raw-token", nil,
+		},
+		{
+			ExtractFromCookie("session"),
+			newReq(func(r *http.Request) { r.AddCookie(&http.Cookie{Name: "session", Value: "cookie-token"}) }),
+			"cookie-token", nil,
+		},
+		{
+			ExtractFromCookie("session"),
+			newReq(func(r *http.Request) {}),
+			"", ErrTokenNotFound,
+		},
+		{
+			ExtractFromQuery("access_token"),
+			newReq(func(r *http.Request) {}),
+			"query-token", nil,
+		},
+		{
+			ExtractFromQuery("token"),
+			newReq(func(r *http.Request) {}),
+			"", ErrTokenNotFound,
+		},
+		{
+			ExtractFromForm("access_token"),
+			newReq(func(r *http.Request) {}),
+			"", ErrTokenNotFound,
+		},
+		{
+			ExtractFirst(ExtractFromAuthHeader(), ExtractFromCookie("session")),
+			newReq(func(r *http.Request) { r.AddCookie(&http.Cookie{Name: "session", Value: "cookie-token"}) }),
+			"cookie-token", nil,
+		},
+		{
+			ExtractFirst(ExtractFromAuthHeader(), ExtractFromCookie("session")),
+			newReq(func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer header-token")
+				r.AddCookie(&http.Cookie{Name: "session", Value: "cookie-token"})
+			}),
+			"header-token", nil,
+		},
+		{
+			ExtractFirst(ExtractFromAuthHeader(), ExtractFromCookie("session")),
+			newReq(func(r *http.Request) {}),
+			"", ErrTokenNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		raw, err := tc.extractor.Extract(tc.req)
+		mustEqual(t, err, tc.wantErr)
+		mustEqual(t, string(raw), tc.want)
+	}
+}
+
+func TestExtractFromForm(t *testing.T) {
+	body := url.Values{"access_token": {"form-token"}}.Encode()
+	r := httptest.NewRequest(http.MethodPost, "/?access_token=query-token", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	raw, err := ExtractFromForm("access_token").Extract(r)
+	mustOk(t, err)
+	mustEqual(t, string(raw), "form-token")
+}
+
+func TestExtractFirstError(t *testing.T) {
+	errBoom := errors.New("boom")
+	extractor := ExtractFirst(
+		ExtractorFunc(func(r *http.Request) ([]byte, error) { return nil, errBoom }),
+		ExtractFromQuery("access_token"),
+	)
+
+	_, err := extractor.Extract(httptest.NewRequest(http.MethodGet, "/?access_token=token", nil))
+	mustEqual(t, err, errBoom)
+}
+
+func TestParseRequest(t *testing.T) {
+	signer := must(NewSignerHS(HS256, hsKey256))
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+	token := must(NewBuilder(signer).Build(&RegisteredClaims{ID: "id"}))
+
+	extractor := ExtractFirst(ExtractFromAuthHeader(), ExtractFromCookie("session"))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: "session", Value: token.String()})
+
+	parsed, err := ParseRequest(r, extractor, verifier)
+	mustOk(t, err)
+	mustEqual(t, parsed.String(), token.String())
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	_, err = ParseRequest(r, extractor, verifier)
+	mustEqual(t, err, ErrTokenNotFound)
+}
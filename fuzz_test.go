@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -20,3 +21,25 @@ func FuzzParseNoVerify(f *testing.F) {
 		}
 	})
 }
+
+// How to run: `go test -fuzz=FuzzDecodeRegisteredClaims -parallel=32`
+func FuzzDecodeRegisteredClaims(f *testing.F) {
+	for _, raw := range registeredClaimsCorpus {
+		f.Add([]byte(raw))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		token := &Token{claims: data}
+
+		var want, have RegisteredClaims
+		wantErr := token.DecodeClaims(&want)
+		haveErr := token.DecodeRegisteredClaims(&have)
+
+		if (wantErr == nil) != (haveErr == nil) {
+			t.Fatalf("%q: want err %v, have %v", data, wantErr, haveErr)
+		}
+		if wantErr == nil && !reflect.DeepEqual(have, want) {
+			t.Fatalf("%q: want %+v, have %+v", data, want, have)
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"time"
+)
+
+// IDTokenClaims represents claims of OpenID Connect ID token.
+// See: https://openid.net/specs/openid-connect-core-1_0.html#IDToken
+type IDTokenClaims struct {
+	RegisteredClaims
+
+	// AuthorizedParty claim is the party to which the ID token was issued.
+	AuthorizedParty string `json:"azp,omitempty"`
+
+	// Nonce claim associates a client session with the ID token.
+	Nonce string `json:"nonce,omitempty"`
+
+	// AuthTime claim is the time when the end-user authentication occurred.
+	AuthTime *NumericDate `json:"auth_time,omitempty"`
+
+	// ACR claim is an authentication context class reference.
+	ACR string `json:"acr,omitempty"`
+
+	// AMR claim is a list of authentication methods references.
+	AMR []string `json:"amr,omitempty"`
+
+	// AccessTokenHash claim is a left-half hash of the access token.
+	AccessTokenHash string `json:"at_hash,omitempty"`
+
+	// CodeHash claim is a left-half hash of the authorization code.
+	CodeHash string `json:"c_hash,omitempty"`
+}
+
+// IDTokenParams are per-request values to check ID token against.
+// Empty values are not checked.
+type IDTokenParams struct {
+	// Nonce sent in the authentication request.
+	Nonce string
+
+	// AccessToken returned with the ID token, checked against `at_hash` claim which is required if set.
+	AccessToken string
+
+	// Code returned with the ID token, checked against `c_hash` claim which is required if set.
+	Code string
+}
+
+// IDTokenValidator validates OpenID Connect ID tokens.
+// See: https://openid.net/specs/openid-connect-core-1_0.html#IDTokenValidation
+type IDTokenValidator struct {
+	// Issuer is an expected `iss` claim, required.
+	Issuer string
+
+	// ClientID is an expected `aud` and `azp` value, required.
+	ClientID string
+
+	// MaxAge is a max allowed time since end-user authentication, zero means not checked.
+	MaxAge time.Duration
+
+	// Types are allowed `typ` header values, not checked if empty.
+	// ID tokens usually have `JWT` type or no type at all.
+	Types []string
+
+	// Leeway is an allowed clock skew for time claims.
+	Leeway time.Duration
+
+	// Now returns current time, time.Now is used if nil.
+	Now func() time.Time
+}
+
+// Parse decodes an ID token, verifies it's signature and validates claims.
+func (v *IDTokenValidator) Parse(raw []byte, verifier Verifier, params IDTokenParams) (*IDTokenClaims, error) {
+	token, err := Parse(raw, verifier)
+	if err != nil {
+		return nil, err
+	}
+	return v.ValidateToken(token, params)
+}
+
+// ValidateToken validates claims of already verified ID token.
+// Returns ErrInvalidConfig if Issuer or ClientID is empty.
+func (v *IDTokenValidator) ValidateToken(token *Token, params IDTokenParams) (*IDTokenClaims, error) {
+	if v.Issuer == "" || v.ClientID == "" {
+		return nil, ErrInvalidConfig
+	}
+	if len(v.Types) > 0 {
+		if err := checkType(token.Header(), v.Types); err != nil {
+			return nil, err
+		}
+	}
+	var claims IDTokenClaims
+	if err := token.DecodeClaims(&claims); err != nil {
+		return nil, err
+	}
+
+	var errs claimErrors
+	errs.check(claims.ExpiresAt != nil, "exp", ErrMissingExpiresAt)
+	errs.require(claims.IssuedAt != nil, "iat")
+	errs.require(claims.Issuer != "", "iss")
+	errs.check(claims.Issuer == "" || claims.IsIssuer(v.Issuer), "iss", ErrIssuerMismatch)
+	errs.check(claims.IsForAudience(v.ClientID), "aud", ErrAudienceMismatch)
+	switch {
+	case len(claims.Audience) > 1 && claims.AuthorizedParty == "":
+		errs.check(false, "azp", ErrAuthorizedPartyMismatch)
+	case claims.AuthorizedParty != "":
+		errs.check(constTimeEqual(claims.AuthorizedParty, v.ClientID), "azp", ErrAuthorizedPartyMismatch)
+	}
+	errs.check(params.Nonce == "" || constTimeEqual(claims.Nonce, params.Nonce), "nonce", ErrNonceMismatch)
+	alg := token.Header().Algorithm
+	errs.require(params.AccessToken == "" || claims.AccessTokenHash != "", "at_hash")
+	errs.check(params.AccessToken == "" || claims.AccessTokenHash == "" ||
+		matchLeftHalfHash(alg, params.AccessToken, claims.AccessTokenHash), "at_hash", ErrHashMismatch)
+	errs.require(params.Code == "" || claims.CodeHash != "", "c_hash")
+	errs.check(params.Code == "" || claims.CodeHash == "" ||
+		matchLeftHalfHash(alg, params.Code, claims.CodeHash), "c_hash", ErrHashMismatch)
+
+	now := nowFunc(v.Now)
+	errs.checkTime(&claims.RegisteredClaims, now, v.Leeway)
+	if v.MaxAge > 0 {
+		errs.require(claims.AuthTime != nil, "auth_time")
+		errs.check(claims.AuthTime == nil || !claims.AuthTime.Add(v.MaxAge+v.Leeway).Before(now), "auth_time", ErrAuthTimeTooOld)
+	}
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+// LeftHalfHash returns base64url encoded left-most half of the hash of a value.
+// Hash function is the one used by a given algorithm.
+// It is used for `at_hash` and `c_hash` claims.
+// See: https://openid.net/specs/openid-connect-core-1_0.html#CodeIDToken
+func LeftHalfHash(alg Algorithm, value string) (string, error) {
+	hash, err := hashOf(alg)
+	if err != nil {
+		return "", err
+	}
+	digest, err := hashPayload(hash, []byte(value))
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(digest[:len(digest)/2]), nil
+}
+
+// matchLeftHalfHash reports whether want is a left-half hash of a value, false for unsupported algorithms.
+func matchLeftHalfHash(alg Algorithm, value, want string) bool {
+	have, err := LeftHalfHash(alg, value)
+	return err == nil && constTimeEqual(have, want)
+}
@@ -0,0 +1,148 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLeftHalfHash(t *testing.T) {
+	testCases := []struct {
+		alg     Algorithm
+		value   string
+		want    string
+		wantErr error
+	}{
+		// See: https://openid.net/specs/openid-connect-core-1_0.html#id_token-tokenExample
+		{RS256, "jHkWEdUXMU1BwAsC4vtUsZwnNvTIxEl0z9K3vx5KF0Y", "77QmUPtjPfzWtF2AnpK9RQ", nil},
+		{ES256, "jHkWEdUXMU1BwAsC4vtUsZwnNvTIxEl0z9K3vx5KF0Y", "77QmUPtjPfzWtF2AnpK9RQ", nil},
+		{"none", "value", "", ErrUnsupportedAlg},
+	}
+
+	for _, tc := range testCases {
+		have, err := LeftHalfHash(tc.alg, tc.value)
+		mustEqual(t, err, tc.wantErr)
+		mustEqual(t, have, tc.want)
+	}
+
+	for _, alg := range []Algorithm{HS384, RS512, EdDSA} {
+		hash := must(LeftHalfHash(alg, "value"))
+		h := must(hashOf(alg))
+		mustEqual(t, len(base64ToBytes(hash)), h.Size()/2)
+	}
+}
+
+func TestIDTokenValidator(t *testing.T) {
+	signer := must(NewSignerHS(HS256, hsKey256))
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+	builder := NewBuilder(signer)
+
+	validator := &IDTokenValidator{
+		Issuer:   "https://op.example.com",
+		ClientID: "client",
+		MaxAge:   time.Hour,
+		Leeway:   time.Minute,
+	}
+	params := IDTokenParams{
+		Nonce:       "nonce",
+		AccessToken: "access-token",
+		Code:        "code",
+	}
+
+	testCases := []struct {
+		claims  func(c *IDTokenClaims)
+		params  func(p *IDTokenParams)
+		wantErr error
+	}{
+		{func(c *IDTokenClaims) {}, func(p *IDTokenParams) {}, nil},
+		{func(c *IDTokenClaims) { c.ExpiresAt = nil }, func(p *IDTokenParams) {}, ErrMissingExpiresAt},
+		{func(c *IDTokenClaims) { c.IssuedAt = nil }, func(p *IDTokenParams) {}, ErrMissingClaim},
+		{func(c *IDTokenClaims) { c.Issuer = "https://evil.example.com" }, func(p *IDTokenParams) {}, ErrIssuerMismatch},
+		{func(c *IDTokenClaims) { c.Issuer = "" }, func(p *IDTokenParams) {}, ErrMissingClaim},
+		{func(c *IDTokenClaims) { c.Audience = Audience{"other"} }, func(p *IDTokenParams) {}, ErrAudienceMismatch},
+		{
+			func(c *IDTokenClaims) { c.Audience = Audience{"client", "other"} },
+			func(p *IDTokenParams) {},
+			ErrAuthorizedPartyMismatch,
+		},
+		{
+			func(c *IDTokenClaims) { c.Audience, c.AuthorizedParty = Audience{"client", "other"}, "other" },
+			func(p *IDTokenParams) {},
+			ErrAuthorizedPartyMismatch,
+		},
+		{
+			func(c *IDTokenClaims) { c.Audience, c.AuthorizedParty = Audience{"client", "other"}, "client" },
+			func(p *IDTokenParams) {},
+			nil,
+		},
+		{func(c *IDTokenClaims) { c.Nonce = "replayed" }, func(p *IDTokenParams) {}, ErrNonceMismatch},
+		{func(c *IDTokenClaims) { c.Nonce = "" }, func(p *IDTokenParams) {}, ErrNonceMismatch},
+		{func(c *IDTokenClaims) { c.Nonce = "" }, func(p *IDTokenParams) { p.Nonce = "" }, nil},
+		{func(c *IDTokenClaims) { c.AuthTime = nil }, func(p *IDTokenParams) {}, ErrMissingClaim},
+		{
+			func(c *IDTokenClaims) { c.AuthTime = NewNumericDate(time.Now().Add(-2 * time.Hour)) },
+			func(p *IDTokenParams) {},
+			ErrAuthTimeTooOld,
+		},
+		{
+			func(c *IDTokenClaims) { c.ExpiresAt = NewNumericDate(time.Now().Add(-time.Hour)) },
+			func(p *IDTokenParams) {},
+			ErrTokenExpired,
+		},
+		{func(c *IDTokenClaims) {}, func(p *IDTokenParams) { p.AccessToken = "another" }, ErrHashMismatch},
+		{func(c *IDTokenClaims) {}, func(p *IDTokenParams) { p.Code = "another" }, ErrHashMismatch},
+		{func(c *IDTokenClaims) { c.CodeHash = "" }, func(p *IDTokenParams) { p.Code = "another" }, ErrMissingClaim},
+		{func(c *IDTokenClaims) { c.CodeHash = "" }, func(p *IDTokenParams) {}, ErrMissingClaim},
+		{func(c *IDTokenClaims) { c.AccessTokenHash = "" }, func(p *IDTokenParams) {}, ErrMissingClaim},
+		{func(c *IDTokenClaims) { c.AccessTokenHash = "" }, func(p *IDTokenParams) { p.AccessToken = "" }, nil},
+		{func(c *IDTokenClaims) { c.CodeHash = "" }, func(p *IDTokenParams) { p.Code = "" }, nil},
+	}
+
+	for _, tc := range testCases {
+		now := time.Now()
+		claims := &IDTokenClaims{
+			RegisteredClaims: RegisteredClaims{
+				Issuer:    "https://op.example.com",
+				Subject:   "user",
+				Audience:  Audience{"client"},
+				IssuedAt:  NewNumericDate(now),
+				ExpiresAt: NewNumericDate(now.Add(time.Minute)),
+			},
+			Nonce:           "nonce",
+			AuthTime:        NewNumericDate(now.Add(-time.Minute)),
+			AccessTokenHash: must(LeftHalfHash(HS256, "access-token")),
+			CodeHash:        must(LeftHalfHash(HS256, "code")),
+		}
+		tc.claims(claims)
+
+		p := params
+		tc.params(&p)
+
+		token := must(builder.Build(claims))
+		have, err := validator.Parse(token.Bytes(), verifier, p)
+		mustEqual(t, errors.Is(err, tc.wantErr), true)
+		if err == nil {
+			mustEqual(t, have.Subject, "user")
+		}
+	}
+}
+
+func TestIDTokenValidatorErrors(t *testing.T) {
+	token := must(NewBuilder(must(NewSignerHS(HS256, hsKey256))).Build(&IDTokenClaims{
+		RegisteredClaims: RegisteredClaims{Audience: Audience{"client"}},
+		CodeHash:         "bad-hash",
+	}))
+	params := IDTokenParams{Code: "code"}
+
+	for _, validator := range []*IDTokenValidator{{ClientID: "client"}, {Issuer: "https://op.example.com"}} {
+		_, err := validator.ValidateToken(token, params)
+		mustEqual(t, err, ErrInvalidConfig)
+	}
+
+	validator := &IDTokenValidator{Issuer: "https://op.example.com", ClientID: "client"}
+	_, err := validator.ValidateToken(token, params)
+
+	var validationErr *ValidationError
+	mustEqual(t, errors.As(err, &validationErr), true)
+	mustEqual(t, validationErr.Error(), "token claims are not valid: exp: token expiration is missing; iat: required claim is missing; iss: required claim is missing; c_hash: token hash claim doesn't match")
+}
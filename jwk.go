@@ -0,0 +1,440 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+)
+
+// JWK represents a JSON Web Key.
+// Private members are set only for private keys, see PrivateKey.
+// See: https://datatracker.ietf.org/doc/html/rfc7517
+type JWK struct {
+	KeyType   string    `json:"kty"`
+	KeyID     string    `json:"kid,omitempty"`
+	Use       string    `json:"use,omitempty"`
+	Algorithm Algorithm `json:"alg,omitempty"`
+
+	// EC and OKP keys.
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+
+	// RSA keys.
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// Private key members, D is used by all key types.
+	D  string `json:"d,omitempty"`
+	P  string `json:"p,omitempty"`
+	Q  string `json:"q,omitempty"`
+	DP string `json:"dp,omitempty"`
+	DQ string `json:"dq,omitempty"`
+	QI string `json:"qi,omitempty"`
+}
+
+// JWKS represents a JSON Web Key Set.
+// See: https://datatracker.ietf.org/doc/html/rfc7517#section-5
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// ParseJWK decodes a JWK from a raw JSON.
+func ParseJWK(raw []byte) (*JWK, error) {
+	var jwk JWK
+	if err := json.Unmarshal(raw, &jwk); err != nil {
+		return nil, ErrInvalidKey
+	}
+	if _, err := jwk.PublicKey(); err != nil {
+		return nil, err
+	}
+	return &jwk, nil
+}
+
+// ParseJWKS decodes a JWK Set from a raw JSON.
+func ParseJWKS(raw []byte) (*JWKS, error) {
+	var jwks JWKS
+	if err := json.Unmarshal(raw, &jwks); err != nil {
+		return nil, ErrInvalidKey
+	}
+	return &jwks, nil
+}
+
+// NewJWK returns a JWK for a given public key.
+// Supported keys are *rsa.PublicKey, *ecdsa.PublicKey and ed25519.PublicKey.
+// Private keys are rejected with ErrInvalidKey, see NewPrivateJWK.
+func NewJWK(key crypto.PublicKey) (*JWK, error) {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return &JWK{
+			KeyType: "RSA",
+			N:       b64EncodeString(key.N.Bytes()),
+			E:       b64EncodeString(big.NewInt(int64(key.E)).Bytes()),
+		}, nil
+
+	case *ecdsa.PublicKey:
+		crv, size := curveName(key.Curve)
+		if crv == "" {
+			return nil, ErrUnsupportedAlg
+		}
+		x, y := make([]byte, size), make([]byte, size)
+		key.X.FillBytes(x)
+		key.Y.FillBytes(y)
+		return &JWK{
+			KeyType: "EC",
+			Curve:   crv,
+			X:       b64EncodeString(x),
+			Y:       b64EncodeString(y),
+		}, nil
+
+	case ed25519.PublicKey:
+		if len(key) != ed25519.PublicKeySize {
+			return nil, ErrInvalidKey
+		}
+		return &JWK{
+			KeyType: "OKP",
+			Curve:   "Ed25519",
+			X:       b64EncodeString(key),
+		}, nil
+
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+		return nil, ErrInvalidKey
+
+	case nil:
+		return nil, ErrNilKey
+	default:
+		return nil, ErrUnsupportedAlg
+	}
+}
+
+// NewPrivateJWK returns a JWK with private members for a given private key.
+// Supported keys are *rsa.PrivateKey, *ecdsa.PrivateKey and ed25519.PrivateKey.
+// Use JWK.Public to get a key which is safe to publish.
+func NewPrivateJWK(key crypto.PrivateKey) (*JWK, error) {
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		// multi-prime keys need `oth` member which is rarely supported.
+		if len(key.Primes) != 2 {
+			return nil, ErrUnsupportedAlg
+		}
+		jwk, err := NewJWK(&key.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		p, q := key.Primes[0], key.Primes[1]
+		one := big.NewInt(1)
+		dp := new(big.Int).Mod(key.D, new(big.Int).Sub(p, one))
+		dq := new(big.Int).Mod(key.D, new(big.Int).Sub(q, one))
+		qi := new(big.Int).ModInverse(q, p)
+		if qi == nil {
+			return nil, ErrInvalidKey
+		}
+		jwk.D = b64EncodeString(key.D.Bytes())
+		jwk.P = b64EncodeString(p.Bytes())
+		jwk.Q = b64EncodeString(q.Bytes())
+		jwk.DP = b64EncodeString(dp.Bytes())
+		jwk.DQ = b64EncodeString(dq.Bytes())
+		jwk.QI = b64EncodeString(qi.Bytes())
+		return jwk, nil
+
+	case *ecdsa.PrivateKey:
+		jwk, err := NewJWK(&key.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		_, size := curveName(key.Curve)
+		d := make([]byte, size)
+		key.D.FillBytes(d)
+		jwk.D = b64EncodeString(d)
+		return jwk, nil
+
+	case ed25519.PrivateKey:
+		if len(key) != ed25519.PrivateKeySize {
+			return nil, ErrInvalidKey
+		}
+		jwk, err := NewJWK(key.Public())
+		if err != nil {
+			return nil, err
+		}
+		jwk.D = b64EncodeString(key.Seed())
+		return jwk, nil
+
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		return nil, ErrInvalidKey
+
+	case nil:
+		return nil, ErrNilKey
+	default:
+		return nil, ErrUnsupportedAlg
+	}
+}
+
+// Public returns a copy of a key without private members.
+func (k *JWK) Public() *JWK {
+	pub := *k
+	pub.D, pub.P, pub.Q, pub.DP, pub.DQ, pub.QI = "", "", "", "", "", ""
+	return &pub
+}
+
+// PublicKey returns a decoded public key.
+// Result is one of *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey.
+func (k *JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, errN := b64DecodeString(k.N)
+		e, errE := b64DecodeString(k.E)
+		if errN != nil || errE != nil || len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return nil, ErrInvalidKey
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+
+	case "EC":
+		curve := curveByName(k.Curve)
+		if curve == nil {
+			return nil, ErrUnsupportedAlg
+		}
+		x, errX := b64DecodeString(k.X)
+		y, errY := b64DecodeString(k.Y)
+		if errX != nil || errY != nil {
+			return nil, ErrInvalidKey
+		}
+		key := &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, ErrInvalidKey
+		}
+		return key, nil
+
+	case "OKP":
+		if k.Curve != "Ed25519" {
+			return nil, ErrUnsupportedAlg
+		}
+		x, err := b64DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, ErrInvalidKey
+		}
+		return ed25519.PublicKey(x), nil
+
+	default:
+		return nil, ErrUnsupportedAlg
+	}
+}
+
+// IsPrivate reports whether a key has private members.
+func (k *JWK) IsPrivate() bool {
+	return k.D != ""
+}
+
+// PrivateKey returns a decoded private key.
+// Result is one of *rsa.PrivateKey, *ecdsa.PrivateKey or ed25519.PrivateKey.
+func (k *JWK) PrivateKey() (crypto.PrivateKey, error) {
+	if !k.IsPrivate() {
+		return nil, ErrInvalidKey
+	}
+	pub, err := k.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	d, err := b64DecodeString(k.D)
+	if err != nil || len(d) == 0 {
+		return nil, ErrInvalidKey
+	}
+
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		p, errP := b64DecodeString(k.P)
+		q, errQ := b64DecodeString(k.Q)
+		if errP != nil || errQ != nil || len(p) == 0 || len(q) == 0 {
+			return nil, ErrInvalidKey
+		}
+		key := &rsa.PrivateKey{
+			PublicKey: *pub,
+			D:         new(big.Int).SetBytes(d),
+			Primes:    []*big.Int{new(big.Int).SetBytes(p), new(big.Int).SetBytes(q)},
+		}
+		if err := key.Validate(); err != nil {
+			return nil, ErrInvalidKey
+		}
+		key.Precompute()
+		return key, nil
+
+	case *ecdsa.PublicKey:
+		key := &ecdsa.PrivateKey{PublicKey: *pub, D: new(big.Int).SetBytes(d)}
+		x, y := pub.Curve.ScalarBaseMult(d)
+		if x.Cmp(pub.X) != 0 || y.Cmp(pub.Y) != 0 {
+			return nil, ErrInvalidKey
+		}
+		return key, nil
+
+	case ed25519.PublicKey:
+		if len(d) != ed25519.SeedSize {
+			return nil, ErrInvalidKey
+		}
+		key := ed25519.NewKeyFromSeed(d)
+		if !pub.Equal(key.Public()) {
+			return nil, ErrInvalidKey
+		}
+		return key, nil
+
+	default:
+		return nil, ErrUnsupportedAlg
+	}
+}
+
+// Signer returns a Signer for a given algorithm, key must be private.
+// If key has `alg` parameter it must match a given algorithm.
+func (k *JWK) Signer(alg Algorithm) (Signer, error) {
+	if k.Algorithm != "" && k.Algorithm != alg {
+		return nil, ErrAlgorithmMismatch
+	}
+	key, err := k.PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return newSigner(alg, key)
+}
+
+// Verifier returns a Verifier for a given algorithm.
+// If key has `alg` parameter it must match a given algorithm.
+func (k *JWK) Verifier(alg Algorithm) (Verifier, error) {
+	if k.Algorithm != "" && k.Algorithm != alg {
+		return nil, ErrAlgorithmMismatch
+	}
+	key, err := k.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return newVerifier(alg, key)
+}
+
+// Thumbprint returns base64url encoded SHA-256 JWK Thumbprint.
+// See: https://datatracker.ietf.org/doc/html/rfc7638
+func (k *JWK) Thumbprint() (string, error) {
+	// required members in lexicographic order, values are base64url or names, no escaping is needed.
+	var canonical string
+	switch k.KeyType {
+	case "RSA":
+		canonical = `{"e":"` + k.E + `","kty":"RSA","n":"` + k.N + `"}`
+	case "EC":
+		canonical = `{"crv":"` + k.Curve + `","kty":"EC","x":"` + k.X + `","y":"` + k.Y + `"}`
+	case "OKP":
+		canonical = `{"crv":"` + k.Curve + `","kty":"OKP","x":"` + k.X + `"}`
+	default:
+		return "", ErrUnsupportedAlg
+	}
+	if _, err := k.PublicKey(); err != nil {
+		return "", err
+	}
+
+	digest := sha256.Sum256([]byte(canonical))
+	return b64EncodeString(digest[:]), nil
+}
+
+// algorithms returns algorithms that can be used with a key.
+func (k *JWK) algorithms() []Algorithm {
+	if k.Algorithm != "" {
+		return []Algorithm{k.Algorithm}
+	}
+	switch k.KeyType {
+	case "RSA":
+		return []Algorithm{RS256, RS384, RS512, PS256, PS384, PS512}
+	case "EC":
+		switch k.Curve {
+		case "P-256":
+			return []Algorithm{ES256}
+		case "P-384":
+			return []Algorithm{ES384}
+		case "P-521":
+			return []Algorithm{ES512}
+		}
+	case "OKP":
+		return []Algorithm{EdDSA}
+	}
+	return nil
+}
+
+// newVerifier returns a Verifier for a given algorithm and a public key.
+func newVerifier(alg Algorithm, key crypto.PublicKey) (Verifier, error) {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		switch alg {
+		case RS256, RS384, RS512:
+			return NewVerifierRS(alg, key)
+		case PS256, PS384, PS512:
+			return NewVerifierPS(alg, key)
+		}
+	case *ecdsa.PublicKey:
+		return NewVerifierES(alg, key)
+	case ed25519.PublicKey:
+		if alg == EdDSA {
+			return NewVerifierEdDSA(key)
+		}
+	}
+	return nil, ErrUnsupportedAlg
+}
+
+// newSigner returns a Signer for a given algorithm and a private key.
+func newSigner(alg Algorithm, key crypto.PrivateKey) (Signer, error) {
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		switch alg {
+		case RS256, RS384, RS512:
+			return NewSignerRS(alg, key)
+		case PS256, PS384, PS512:
+			return NewSignerPS(alg, key)
+		}
+	case *ecdsa.PrivateKey:
+		return NewSignerES(alg, key)
+	case ed25519.PrivateKey:
+		if alg == EdDSA {
+			return NewSignerEdDSA(key)
+		}
+	}
+	return nil, ErrUnsupportedAlg
+}
+
+func curveName(curve elliptic.Curve) (string, int) {
+	switch curve {
+	case elliptic.P256():
+		return "P-256", 32
+	case elliptic.P384():
+		return "P-384", 48
+	case elliptic.P521():
+		return "P-521", 66
+	default:
+		return "", 0
+	}
+}
+
+func curveByName(name string) elliptic.Curve {
+	switch name {
+	case "P-256":
+		return elliptic.P256()
+	case "P-384":
+		return elliptic.P384()
+	case "P-521":
+		return elliptic.P521()
+	default:
+		return nil
+	}
+}
+
+func b64EncodeString(src []byte) string {
+	return base64.RawURLEncoding.EncodeToString(src)
+}
+
+func b64DecodeString(src string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(src)
+}
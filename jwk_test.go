@@ -0,0 +1,141 @@
+package jwt
+
+import (
+	"crypto"
+	"encoding/json"
+	"testing"
+)
+
+func TestJWK(t *testing.T) {
+	testCases := []struct {
+		key    crypto.PublicKey
+		signer Signer
+		alg    Algorithm
+	}{
+		{rsaPublicKey256, must(NewSignerRS(RS256, rsaPrivateKey256)), RS256},
+		{rsaPublicKey256, must(NewSignerPS(PS256, rsaPrivateKey256)), PS256},
+		{ecdsaPublicKey256, must(NewSignerES(ES256, ecdsaPrivateKey256)), ES256},
+		{ecdsaPublicKey384, must(NewSignerES(ES384, ecdsaPrivateKey384)), ES384},
+		{ecdsaPublicKey521, must(NewSignerES(ES512, ecdsaPrivateKey521)), ES512},
+		{ed25519PublicKey, must(NewSignerEdDSA(ed25519PrivateKey)), EdDSA},
+	}
+
+	for _, tc := range testCases {
+		jwk, err := NewJWK(tc.key)
+		mustOk(t, err)
+
+		raw, err := json.Marshal(jwk)
+		mustOk(t, err)
+
+		parsed, err := ParseJWK(raw)
+		mustOk(t, err)
+		mustEqual(t, parsed, jwk)
+		mustEqual(t, must(parsed.PublicKey()), tc.key)
+
+		verifier, err := parsed.Verifier(tc.alg)
+		mustOk(t, err)
+
+		token := must(NewBuilder(tc.signer).Build(simplePayload))
+		mustOk(t, verifier.Verify(token))
+	}
+}
+
+func TestJWKBad(t *testing.T) {
+	testCases := []struct {
+		raw     string
+		wantErr error
+	}{
+		{`not a json`, ErrInvalidKey},
+		{`{"kty":"oct","k":"c2VjcmV0"}`, ErrUnsupportedAlg},
+		{`{"kty":"RSA","n":"!","e":"AQAB"}`, ErrInvalidKey},
+		{`{"kty":"RSA","n":"AQAB"}`, ErrInvalidKey},
+		{`{"kty":"EC","crv":"P-192","x":"AQAB","y":"AQAB"}`, ErrUnsupportedAlg},
+		{`{"kty":"EC","crv":"P-256","x":"AQAB","y":"AQAB"}`, ErrInvalidKey},
+		{`{"kty":"OKP","crv":"X25519","x":"AQAB"}`, ErrUnsupportedAlg},
+		{`{"kty":"OKP","crv":"Ed25519","x":"AQAB"}`, ErrInvalidKey},
+	}
+
+	for _, tc := range testCases {
+		_, err := ParseJWK([]byte(tc.raw))
+		mustEqual(t, err, tc.wantErr)
+	}
+
+	jwk := must(NewJWK(rsaPublicKey256))
+	jwk.Algorithm = RS256
+	_, err := jwk.Verifier(PS256)
+	mustEqual(t, err, ErrAlgorithmMismatch)
+
+	_, err = must(NewJWK(ed25519PublicKey)).Verifier(ES256)
+	mustEqual(t, err, ErrUnsupportedAlg)
+
+	_, err = NewJWK(nil)
+	mustEqual(t, err, ErrNilKey)
+
+	for _, key := range []any{rsaPrivateKey256, ecdsaPrivateKey256, ed25519PrivateKey} {
+		_, err = NewJWK(key)
+		mustEqual(t, err, ErrInvalidKey)
+	}
+	for _, key := range []any{rsaPublicKey256, ecdsaPublicKey256, ed25519PublicKey} {
+		_, err = NewPrivateJWK(key)
+		mustEqual(t, err, ErrInvalidKey)
+	}
+}
+
+func TestJWKPrivate(t *testing.T) {
+	testCases := []struct {
+		key      crypto.PrivateKey
+		verifier Verifier
+		alg      Algorithm
+	}{
+		{rsaPrivateKey256, must(NewVerifierRS(RS256, rsaPublicKey256)), RS256},
+		{rsaPrivateKey256, must(NewVerifierPS(PS256, rsaPublicKey256)), PS256},
+		{ecdsaPrivateKey256, must(NewVerifierES(ES256, ecdsaPublicKey256)), ES256},
+		{ecdsaPrivateKey521, must(NewVerifierES(ES512, ecdsaPublicKey521)), ES512},
+		{ed25519PrivateKey, must(NewVerifierEdDSA(ed25519PublicKey)), EdDSA},
+	}
+
+	for _, tc := range testCases {
+		jwk := must(NewPrivateJWK(tc.key))
+		mustEqual(t, jwk.IsPrivate(), true)
+		mustEqual(t, jwk.Public().IsPrivate(), false)
+
+		raw, err := json.Marshal(jwk)
+		mustOk(t, err)
+
+		parsed, err := ParseJWK(raw)
+		mustOk(t, err)
+
+		signer, err := parsed.Signer(tc.alg)
+		mustOk(t, err)
+
+		token := must(NewBuilder(signer).Build(simplePayload))
+		mustOk(t, tc.verifier.Verify(token))
+	}
+
+	_, err := must(NewJWK(ecdsaPublicKey256)).Signer(ES256)
+	mustEqual(t, err, ErrInvalidKey)
+
+	jwk := must(NewPrivateJWK(ecdsaPrivateKey256))
+	jwk.D = must(NewPrivateJWK(ecdsaPrivateKey256Another)).D
+	_, err = jwk.Signer(ES256)
+	mustEqual(t, err, ErrInvalidKey)
+
+	jwk = must(NewPrivateJWK(ed25519PrivateKey))
+	jwk.D = must(NewPrivateJWK(ed25519PrivateKeyAnother)).D
+	_, err = jwk.Signer(EdDSA)
+	mustEqual(t, err, ErrInvalidKey)
+
+	jwk = must(NewPrivateJWK(rsaPrivateKey256))
+	jwk.P = jwk.Q
+	_, err = jwk.Signer(RS256)
+	mustEqual(t, err, ErrInvalidKey)
+
+	jwk = must(NewPrivateJWK(rsaPrivateKey256))
+	mustEqual(t, jwk.DP, b64EncodeString(rsaPrivateKey256.Precomputed.Dp.Bytes()))
+	mustEqual(t, jwk.DQ, b64EncodeString(rsaPrivateKey256.Precomputed.Dq.Bytes()))
+	mustEqual(t, jwk.QI, b64EncodeString(rsaPrivateKey256.Precomputed.Qinv.Bytes()))
+
+	jwk.Algorithm = RS256
+	_, err = jwk.Signer(PS256)
+	mustEqual(t, err, ErrAlgorithmMismatch)
+}
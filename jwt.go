@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/rand"
 	"encoding/json"
+	"unicode/utf8"
 )
 
 // Token represents a JWT token.
@@ -15,6 +16,7 @@ type Token struct {
 	signature []byte
 	header    Header
 	claims    json.RawMessage
+	buf       []byte // decoded parts, reused by ParseInto
 }
 
 func (t *Token) String() string {
@@ -56,8 +58,12 @@ func (t *Token) Claims() json.RawMessage {
 }
 
 // DecodeClaims into a given parameter.
+// JSON errors are returned as ParseError.
 func (t *Token) DecodeClaims(dst any) error {
-	return json.Unmarshal(t.claims, dst)
+	if err := json.Unmarshal(t.claims, dst); err != nil {
+		return &ParseError{Segment: "claims", Err: err}
+	}
+	return nil
 }
 
 // Signature returns token's signature.
@@ -74,34 +80,64 @@ func (t *Token) isValid() bool {
 // See: https://tools.ietf.org/html/rfc7519#section-5, https://tools.ietf.org/html/rfc7517
 type Header struct {
 	Algorithm   Algorithm `json:"alg"`
-	Type        string    `json:"typ,omitempty"` // only "JWT" can be here
+	Type        string    `json:"typ,omitempty"` // "JWT" by default, see WithType
 	ContentType string    `json:"cty,omitempty"`
 	KeyID       string    `json:"kid,omitempty"`
+	JWK         *JWK      `json:"jwk,omitempty"`   // public key, used by DPoP proofs
+	Nonce       string    `json:"nonce,omitempty"` // anti-replay nonce, used by ACME requests
 }
 
 // MarshalJSON implements the json.Marshaler interface.
 func (h Header) MarshalJSON() ([]byte, error) {
 	buf := bytes.Buffer{}
-	buf.WriteString(`{"alg":"`)
-	buf.WriteString(string(h.Algorithm))
+	buf.WriteString(`{"alg":`)
+	writeJSONString(&buf, string(h.Algorithm))
 
 	if h.Type != "" {
-		buf.WriteString(`","typ":"`)
-		buf.WriteString(h.Type)
+		buf.WriteString(`,"typ":`)
+		writeJSONString(&buf, h.Type)
 	}
 	if h.ContentType != "" {
-		buf.WriteString(`","cty":"`)
-		buf.WriteString(h.ContentType)
+		buf.WriteString(`,"cty":`)
+		writeJSONString(&buf, h.ContentType)
 	}
 	if h.KeyID != "" {
-		buf.WriteString(`","kid":"`)
-		buf.WriteString(h.KeyID)
+		buf.WriteString(`,"kid":`)
+		writeJSONString(&buf, h.KeyID)
+	}
+	if h.Nonce != "" {
+		buf.WriteString(`,"nonce":`)
+		writeJSONString(&buf, h.Nonce)
 	}
-	buf.WriteString(`"}`)
+
+	if h.JWK != nil {
+		jwk, err := json.Marshal(h.JWK)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(`,"jwk":`)
+		buf.Write(jwk)
+	}
+	buf.WriteByte('}')
 
 	return buf.Bytes(), nil
 }
 
+// writeJSONString writes a quoted JSON string, escaping is done only if needed.
+func writeJSONString(buf *bytes.Buffer, s string) {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c == '"' || c == '\\' || c >= utf8.RuneSelf {
+			// returned err is always nil for strings
+			quoted, _ := json.Marshal(s)
+			buf.Write(quoted)
+			return
+		}
+	}
+	buf.WriteByte('"')
+	buf.WriteString(s)
+	buf.WriteByte('"')
+}
+
 // Generates a random key of the given bits length.
 func GenerateRandomBits(bits int) ([]byte, error) {
 	key := make([]byte, bits/8)
@@ -110,3 +146,12 @@ func GenerateRandomBits(bits int) ([]byte, error) {
 	}
 	return key, nil
 }
+
+// newTokenID returns a random 128-bit base64url encoded string, suitable for `jti` claim.
+func newTokenID() (string, error) {
+	id, err := GenerateRandomBits(128)
+	if err != nil {
+		return "", err
+	}
+	return b64EncodeString(id), nil
+}
@@ -139,9 +139,53 @@ func BenchmarkAlgHS(b *testing.B) {
 		b.Run("Verify-"+string(algo), func(b *testing.B) {
 			runVerifyBench(b, builder, verifier)
 		})
+		b.Run("AppendSign-"+string(algo), func(b *testing.B) {
+			runAppendSignerBench(b, builder)
+		})
+		b.Run("VerifyBytes-"+string(algo), func(b *testing.B) {
+			runVerifyBytesBench(b, builder, verifier)
+		})
 	}
 }
 
+func runVerifyBytesBench(b *testing.B, builder *jwt.Builder, verifier jwt.Verifier) {
+	b.Helper()
+	token, err := builder.Build(jwt.RegisteredClaims{
+		ID:       "id",
+		Issuer:   "sdf",
+		IssuedAt: jwt.NewNumericDate(time.Now()),
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	raw := token.Bytes()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := jwt.VerifyBytes(raw, verifier); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func runAppendSignerBench(b *testing.B, builder *jwt.Builder) {
+	b.Helper()
+	b.ReportAllocs()
+
+	claims := []byte(`{"jti":"id","iss":"sdf","iat":1700000000}`)
+	buf := make([]byte, 0, 512)
+
+	var dummy int
+	for i := 0; i < b.N; i++ {
+		token, err := builder.AppendBuildRaw(buf[:0], claims)
+		if err != nil {
+			b.Fatal(err)
+		}
+		dummy += int(token[0])
+	}
+	sink(dummy)
+}
+
 func runSignerBench(b *testing.B, builder *jwt.Builder) {
 	b.Helper()
 	b.ReportAllocs()
@@ -56,6 +56,10 @@ func TestMarshalHeader(t *testing.T) {
 			&Header{Algorithm: RS256, Type: "JwT", ContentType: "token", KeyID: "test"},
 			`{"alg":"RS256","typ":"JwT","cty":"token","kid":"test"}`,
 		},
+		{
+			&Header{Algorithm: ES256, Type: "dpop+jwt", JWK: &JWK{KeyType: "EC", Curve: "P-256", X: "eA", Y: "eQ"}},
+			`{"alg":"ES256","typ":"dpop+jwt","jwk":{"kty":"EC","crv":"P-256","x":"eA","y":"eQ"}}`,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,281 @@
+package jwt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// KeySet is a Verifier backed by a JWK Set.
+// Key is selected by token `kid` header, all keys are tried if header is empty.
+// Safe to use concurrently.
+type KeySet struct {
+	keys []keySetEntry
+}
+
+type keySetEntry struct {
+	kid      string
+	verifier Verifier
+}
+
+// NewKeySet returns a new KeySet restricted to given algorithms.
+// If no algorithms are given every algorithm suitable for a key is allowed.
+// Keys that cannot be used for signature verification are skipped.
+func NewKeySet(jwks *JWKS, algs ...Algorithm) (*KeySet, error) {
+	ks := &KeySet{}
+	for i := range jwks.Keys {
+		key := &jwks.Keys[i]
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		for _, alg := range key.algorithms() {
+			if len(algs) > 0 && !containsAlg(algs, alg) {
+				continue
+			}
+			verifier, err := key.Verifier(alg)
+			if err != nil {
+				continue
+			}
+			ks.keys = append(ks.keys, keySetEntry{kid: key.KeyID, verifier: verifier})
+		}
+	}
+	if len(ks.keys) == 0 {
+		return nil, ErrKeyNotFound
+	}
+	return ks, nil
+}
+
+// Algorithm returns an empty string because KeySet supports many algorithms.
+func (ks *KeySet) Algorithm() Algorithm {
+	return ""
+}
+
+// Verify implements Verifier interface.
+func (ks *KeySet) Verify(token *Token) error {
+	if !token.isValid() {
+		return ErrUninitializedToken
+	}
+	header := token.Header()
+
+	err := ErrKeyNotFound
+	for _, key := range ks.keys {
+		if header.KeyID != "" && key.kid != header.KeyID {
+			continue
+		}
+		if !constTimeAlgEqual(header.Algorithm, key.verifier.Algorithm()) {
+			continue
+		}
+		if err = key.verifier.Verify(token); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// RemoteKeySet is a Verifier backed by a JWK Set fetched from a URL.
+// Keys are refreshed after a TTL or when a token has an unknown `kid`,
+// but not more often than once in a MinRefresh interval, failed fetches included.
+// If a refresh fails, previously fetched keys are used.
+// Safe to use concurrently.
+type RemoteKeySet struct {
+	url    string
+	client *http.Client
+	algs   []Algorithm
+
+	// TTL is a max time to keep keys, default is 1 hour.
+	TTL time.Duration
+
+	// MinRefresh is a min interval between fetches, default is 1 minute.
+	MinRefresh time.Duration
+
+	// FetchTimeout limits a single fetch, so a hung endpoint doesn't block Verify, default is 10 seconds.
+	// Zero means no limit besides the client timeout.
+	FetchTimeout time.Duration
+
+	mu          sync.RWMutex
+	keys        *KeySet
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	fetchErr    error
+	fetchMu     sync.Mutex
+	now         func() time.Time
+}
+
+// NewRemoteKeySet returns a new RemoteKeySet restricted to given algorithms.
+// Keys are fetched lazily on first Verify call, see also Refresh.
+// If client is nil http.DefaultClient is used, fetches are still limited by FetchTimeout.
+func NewRemoteKeySet(url string, client *http.Client, algs ...Algorithm) *RemoteKeySet {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &RemoteKeySet{
+		url:          url,
+		client:       client,
+		algs:         algs,
+		TTL:          time.Hour,
+		MinRefresh:   time.Minute,
+		FetchTimeout: 10 * time.Second,
+		now:          time.Now,
+	}
+}
+
+// Algorithm returns an empty string because RemoteKeySet supports many algorithms.
+func (rs *RemoteKeySet) Algorithm() Algorithm {
+	return ""
+}
+
+// Verify implements Verifier interface.
+func (rs *RemoteKeySet) Verify(token *Token) error {
+	if !token.isValid() {
+		return ErrUninitializedToken
+	}
+
+	rs.mu.RLock()
+	keys, fetchedAt, attemptedAt, fetchErr := rs.keys, rs.fetchedAt, rs.attemptedAt, rs.fetchErr
+	rs.mu.RUnlock()
+
+	canRefresh := attemptedAt.IsZero() || rs.now().Sub(attemptedAt) >= rs.MinRefresh
+	switch {
+	case keys == nil && !canRefresh:
+		return fetchErr
+	case keys == nil || (canRefresh && rs.now().Sub(fetchedAt) > rs.TTL):
+		if err := rs.refresh(context.Background(), attemptedAt); err != nil && keys == nil {
+			return err
+		}
+		keys, attemptedAt = rs.current()
+		canRefresh = false
+	}
+
+	err := keys.Verify(token)
+	if err != ErrKeyNotFound || !canRefresh {
+		return err
+	}
+
+	// unknown key, probably keys were rotated.
+	if err := rs.refresh(context.Background(), attemptedAt); err != nil {
+		return err
+	}
+	keys, _ = rs.current()
+	return keys.Verify(token)
+}
+
+// Refresh fetches keys.
+func (rs *RemoteKeySet) Refresh(ctx context.Context) error {
+	_, attemptedAt := rs.current()
+	return rs.refresh(ctx, attemptedAt)
+}
+
+func (rs *RemoteKeySet) current() (*KeySet, time.Time) {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	return rs.keys, rs.attemptedAt
+}
+
+// refresh fetches keys if there was no fetch after a given time by a concurrent call.
+// Time of a failed fetch is recorded too, so a broken endpoint isn't requested on every call.
+func (rs *RemoteKeySet) refresh(ctx context.Context, seen time.Time) error {
+	rs.fetchMu.Lock()
+	defer rs.fetchMu.Unlock()
+
+	rs.mu.RLock()
+	attemptedAt, fetchErr := rs.attemptedAt, rs.fetchErr
+	rs.mu.RUnlock()
+	if attemptedAt.After(seen) {
+		return fetchErr
+	}
+
+	keys, err := rs.fetch(ctx)
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.attemptedAt, rs.fetchErr = rs.now(), err
+	if err != nil {
+		return err
+	}
+	rs.keys, rs.fetchedAt = keys, rs.attemptedAt
+	return nil
+}
+
+// fetch returns errors as KeyFetchError, so they aren't mistaken for token errors.
+func (rs *RemoteKeySet) fetch(ctx context.Context) (*KeySet, error) {
+	if rs.FetchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, rs.FetchTimeout)
+		defer cancel()
+	}
+	body, err := httpGet(ctx, rs.client, rs.url)
+	if err != nil {
+		return nil, &KeyFetchError{URL: rs.url, Err: err}
+	}
+	jwks, err := ParseJWKS(body)
+	if err != nil {
+		return nil, &KeyFetchError{URL: rs.url, Err: err}
+	}
+	keys, err := NewKeySet(jwks, rs.algs...)
+	if err != nil {
+		return nil, &KeyFetchError{URL: rs.url, Err: err}
+	}
+	return keys, nil
+}
+
+// maxResponseSize limits size of fetched documents.
+const maxResponseSize = 1 << 20
+
+func httpGet(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &HTTPStatusError{URL: url, StatusCode: resp.StatusCode}
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+}
+
+// HTTPStatusError is returned when a remote document cannot be fetched.
+type HTTPStatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *HTTPStatusError) Error() string {
+	return "unexpected status " + strconv.Itoa(e.StatusCode) + " from " + e.URL
+}
+
+// KeyFetchError is returned when remote keys cannot be fetched or are not valid.
+// It is a server error even if the underlying error is ErrInvalidKey or ErrKeyNotFound.
+type KeyFetchError struct {
+	URL string
+	Err error
+}
+
+func (e *KeyFetchError) Error() string {
+	msg := "cannot fetch keys from " + e.URL
+	if e.Err == nil {
+		return msg
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *KeyFetchError) Unwrap() error { return e.Err }
+
+func containsAlg(algs []Algorithm, alg Algorithm) bool {
+	for _, a := range algs {
+		if a == alg {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestKeySet(t *testing.T) {
+	jwks := newTestJWKS()
+
+	ks, err := NewKeySet(jwks)
+	mustOk(t, err)
+
+	testCases := []struct {
+		signer  Signer
+		opts    []BuilderOption
+		wantErr error
+	}{
+		{must(NewSignerRS(RS256, rsaPrivateKey256)), []BuilderOption{WithKeyID("rsa")}, nil},
+		{must(NewSignerRS(RS256, rsaPrivateKey256)), nil, nil},
+		{must(NewSignerES(ES256, ecdsaPrivateKey256)), []BuilderOption{WithKeyID("ec")}, nil},
+		{must(NewSignerEdDSA(ed25519PrivateKey)), []BuilderOption{WithKeyID("ed")}, nil},
+		{must(NewSignerRS(RS256, rsaPrivateKey256)), []BuilderOption{WithKeyID("ec")}, ErrKeyNotFound},
+		{must(NewSignerRS(RS256, rsaPrivateKey256)), []BuilderOption{WithKeyID("unknown")}, ErrKeyNotFound},
+		{must(NewSignerRS(RS384, rsaPrivateKey256)), []BuilderOption{WithKeyID("rsa")}, ErrKeyNotFound},
+		{must(NewSignerRS(RS256, rsaPrivateKey384)), []BuilderOption{WithKeyID("rsa")}, ErrInvalidSignature},
+		{must(NewSignerHS(HS256, hsKey256)), []BuilderOption{WithKeyID("rsa")}, ErrKeyNotFound},
+	}
+
+	for _, tc := range testCases {
+		token := must(NewBuilder(tc.signer, tc.opts...).Build(simplePayload))
+		mustEqual(t, ks.Verify(token), tc.wantErr)
+	}
+}
+
+func TestKeySetAlgorithms(t *testing.T) {
+	ks, err := NewKeySet(newTestJWKS(), ES256)
+	mustOk(t, err)
+
+	rsToken := must(NewBuilder(must(NewSignerRS(RS256, rsaPrivateKey256)), WithKeyID("rsa")).Build(simplePayload))
+	mustEqual(t, ks.Verify(rsToken), ErrKeyNotFound)
+
+	esToken := must(NewBuilder(must(NewSignerES(ES256, ecdsaPrivateKey256)), WithKeyID("ec")).Build(simplePayload))
+	mustOk(t, ks.Verify(esToken))
+
+	_, err = NewKeySet(newTestJWKS(), HS256)
+	mustEqual(t, err, ErrKeyNotFound)
+}
+
+func TestRemoteKeySet(t *testing.T) {
+	var hits int32
+	jwks := &JWKS{Keys: []JWK{*must(NewJWK(ecdsaPublicKey256))}}
+	jwks.Keys[0].KeyID = "old"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		mustOk(t, json.NewEncoder(w).Encode(jwks))
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	rs := NewRemoteKeySet(srv.URL, srv.Client(), ES256)
+	rs.now = func() time.Time { return now }
+
+	signer := must(NewSignerES(ES256, ecdsaPrivateKey256))
+	oldToken := must(NewBuilder(signer, WithKeyID("old")).Build(simplePayload))
+	newToken := must(NewBuilder(signer, WithKeyID("new")).Build(simplePayload))
+
+	mustOk(t, rs.Verify(oldToken))
+	mustOk(t, rs.Verify(oldToken))
+	mustEqual(t, atomic.LoadInt32(&hits), int32(1))
+
+	// unknown key, but keys were just fetched.
+	mustEqual(t, rs.Verify(newToken), ErrKeyNotFound)
+	mustEqual(t, atomic.LoadInt32(&hits), int32(1))
+
+	// keys were rotated.
+	jwks.Keys[0].KeyID = "new"
+	now = now.Add(2 * time.Minute)
+	mustOk(t, rs.Verify(newToken))
+	mustEqual(t, atomic.LoadInt32(&hits), int32(2))
+
+	// TTL is passed.
+	now = now.Add(2 * time.Hour)
+	mustOk(t, rs.Verify(newToken))
+	mustEqual(t, atomic.LoadInt32(&hits), int32(3))
+}
+
+func TestRemoteKeySetBadResponse(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	rs := NewRemoteKeySet(srv.URL, srv.Client())
+	token := must(NewBuilder(must(NewSignerES(ES256, ecdsaPrivateKey256))).Build(simplePayload))
+
+	err := rs.Verify(token)
+	var statusErr *HTTPStatusError
+	mustEqual(t, errors.As(err, &statusErr), true)
+	mustEqual(t, statusErr.StatusCode, http.StatusNotFound)
+
+	// failed fetch is not repeated before MinRefresh.
+	mustEqual(t, rs.Verify(token), err)
+	mustEqual(t, atomic.LoadInt32(&hits), int32(1))
+}
+
+func TestRemoteKeySetRefreshFailed(t *testing.T) {
+	var hits, broken int32
+	jwks := &JWKS{Keys: []JWK{*must(NewJWK(ecdsaPublicKey256))}}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if atomic.LoadInt32(&broken) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		mustOk(t, json.NewEncoder(w).Encode(jwks))
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	rs := NewRemoteKeySet(srv.URL, srv.Client(), ES256)
+	rs.now = func() time.Time { return now }
+
+	token := must(NewBuilder(must(NewSignerES(ES256, ecdsaPrivateKey256))).Build(simplePayload))
+	mustOk(t, rs.Verify(token))
+
+	// TTL is passed, but endpoint is down: cached keys are used.
+	atomic.StoreInt32(&broken, 1)
+	now = now.Add(2 * time.Hour)
+	mustOk(t, rs.Verify(token))
+	mustOk(t, rs.Verify(token))
+	mustEqual(t, atomic.LoadInt32(&hits), int32(2))
+
+	// failed refresh is retried after MinRefresh.
+	atomic.StoreInt32(&broken, 0)
+	now = now.Add(2 * time.Minute)
+	mustOk(t, rs.Verify(token))
+	mustOk(t, rs.Verify(token))
+	mustEqual(t, atomic.LoadInt32(&hits), int32(3))
+}
+
+func TestRemoteKeySetBadKeys(t *testing.T) {
+	token := must(NewBuilder(must(NewSignerES(ES256, ecdsaPrivateKey256))).Build(simplePayload))
+
+	testCases := []struct {
+		body    string
+		wantErr error
+	}{
+		{`{"keys":[]}`, ErrKeyNotFound},
+		{`{"keys":[{"kty":"EC","crv":"P-256","x":"bad"}]}`, ErrKeyNotFound},
+		{`{"keys":`, ErrInvalidKey},
+	}
+
+	for _, tc := range testCases {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(tc.body))
+		}))
+
+		err := NewRemoteKeySet(srv.URL, srv.Client(), ES256).Verify(token)
+		srv.Close()
+
+		var fetchErr *KeyFetchError
+		mustEqual(t, errors.As(err, &fetchErr), true)
+		mustEqual(t, errors.Is(err, tc.wantErr), true)
+		mustEqual(t, NewBearerError(err, false).StatusCode, http.StatusInternalServerError)
+	}
+}
+
+func TestRemoteKeySetFetchTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	rs := NewRemoteKeySet(srv.URL, srv.Client())
+	rs.FetchTimeout = 50 * time.Millisecond
+	token := must(NewBuilder(must(NewSignerES(ES256, ecdsaPrivateKey256))).Build(simplePayload))
+
+	err := rs.Verify(token)
+	mustEqual(t, errors.Is(err, context.DeadlineExceeded), true)
+}
+
+func newTestJWKS() *JWKS {
+	rsa := must(NewJWK(rsaPublicKey256))
+	rsa.KeyID, rsa.Algorithm = "rsa", RS256
+
+	ec := must(NewJWK(ecdsaPublicKey256))
+	ec.KeyID = "ec"
+
+	ed := must(NewJWK(ed25519PublicKey))
+	ed.KeyID = "ed"
+
+	enc := must(NewJWK(ecdsaPublicKey384))
+	enc.KeyID, enc.Use = "enc", "enc"
+
+	return &JWKS{Keys: []JWK{*rsa, *ec, *ed, *enc}}
+}
@@ -0,0 +1,173 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// BackChannelLogoutEvent is an event identifier of back-channel logout tokens.
+const BackChannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// LogoutTokenClaims represents claims of OpenID Connect back-channel logout token.
+// See: https://openid.net/specs/openid-connect-backchannel-1_0.html#LogoutToken
+type LogoutTokenClaims struct {
+	RegisteredClaims
+
+	// Events claim, must contain BackChannelLogoutEvent.
+	Events map[string]json.RawMessage `json:"events"`
+
+	// SessionID claim identifies a session at the provider.
+	SessionID string `json:"sid,omitempty"`
+}
+
+// LogoutTokenValidator validates back-channel logout tokens.
+// See: https://openid.net/specs/openid-connect-backchannel-1_0.html#Validation
+type LogoutTokenValidator struct {
+	// Issuer is an expected `iss` claim, required.
+	Issuer string
+
+	// ClientID is an expected `aud` value, required.
+	ClientID string
+
+	// AllowUntyped accepts tokens without `typ` header or with a generic `JWT` type.
+	// By default only `logout+jwt` type is accepted.
+	AllowUntyped bool
+
+	// Types are allowed `typ` header values, overrides default types and AllowUntyped if not empty.
+	Types []string
+
+	// MaxAge is a max allowed age of the token by `iat` claim, zero means not checked.
+	MaxAge time.Duration
+
+	// Replay rejects an already used token, not checked if nil.
+	Replay *ReplayGuard
+
+	// Leeway is an allowed clock skew for time claims.
+	Leeway time.Duration
+
+	// Now returns current time, time.Now is used if nil.
+	Now func() time.Time
+}
+
+// Parse decodes a logout token, verifies it's signature and validates claims.
+// Returns ErrInvalidConfig if Issuer or ClientID is empty.
+func (v *LogoutTokenValidator) Parse(raw []byte, verifier Verifier) (*LogoutTokenClaims, error) {
+	if v.Issuer == "" || v.ClientID == "" {
+		return nil, ErrInvalidConfig
+	}
+
+	token, err := Parse(raw, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	types := []string{TypeLogout}
+	if v.AllowUntyped {
+		types = append(types, "", TypeJWT)
+	}
+	if err := checkType(token.Header(), typesOr(v.Types, types...)); err != nil {
+		return nil, err
+	}
+
+	var claims LogoutTokenClaims
+	if err := token.DecodeClaims(&claims); err != nil {
+		return nil, err
+	}
+	var nonce struct {
+		Nonce json.RawMessage `json:"nonce"`
+	}
+	if err := token.DecodeClaims(&nonce); err != nil {
+		return nil, err
+	}
+
+	var errs claimErrors
+	errs.require(claims.Issuer != "", "iss")
+	errs.check(claims.Issuer == "" || claims.IsIssuer(v.Issuer), "iss", ErrIssuerMismatch)
+	errs.check(claims.IsForAudience(v.ClientID), "aud", ErrAudienceMismatch)
+	errs.require(claims.IssuedAt != nil, "iat")
+	errs.check(claims.ExpiresAt != nil, "exp", ErrMissingExpiresAt)
+	errs.check(claims.ID != "", "jti", ErrMissingID)
+	errs.require(claims.Subject != "" || claims.SessionID != "", "sid")
+	errs.check(nonce.Nonce == nil, "nonce", ErrNonceMismatch)
+	errs.require(isJSONObject(claims.Events[BackChannelLogoutEvent]), "events")
+
+	now := nowFunc(v.Now)
+	errs.checkTime(&claims.RegisteredClaims, now, v.Leeway)
+	if v.MaxAge > 0 {
+		errs.checkAge(claims.IssuedAt, now, v.MaxAge, v.Leeway)
+	}
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	if v.Replay != nil {
+		if err := v.Replay.Use(claims.ID, claims.ExpiresAt.Time); err != nil {
+			return nil, err
+		}
+	}
+	return &claims, nil
+}
+
+// NewLogoutHandler returns an http.Handler for back-channel logout requests.
+// Handler accepts form-posted `logout_token`, validates it and calls logout func to terminate sessions.
+// Invalid tokens are answered with 400 and an error description.
+// Logout func and other server errors are answered with 400 too, as the spec requires, but without details.
+// See: https://openid.net/specs/openid-connect-backchannel-1_0.html#BCRequest
+func NewLogoutHandler(verifier Verifier, validator *LogoutTokenValidator, logout func(ctx context.Context, claims *LogoutTokenClaims) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		raw, err := ExtractFromForm("logout_token").Extract(r)
+		if err != nil {
+			writeLogoutError(w, err)
+			return
+		}
+		claims, err := validator.Parse(raw, verifier)
+		if err != nil {
+			if !isInvalidToken(err) {
+				writeLogoutFailed(w)
+				return
+			}
+			writeLogoutError(w, err)
+			return
+		}
+		if err := logout(r.Context(), claims); err != nil {
+			writeLogoutFailed(w)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func writeLogoutError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"error":             "invalid_request",
+		"error_description": err.Error(),
+	})
+}
+
+// writeLogoutFailed responds to errors which are not caused by the token, details are not exposed.
+// See: https://openid.net/specs/openid-connect-backchannel-1_0.html#BCResponse
+func writeLogoutFailed(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"error": "logout_failed",
+	})
+}
+
+func isJSONObject(raw json.RawMessage) bool {
+	raw = bytes.TrimSpace(raw)
+	return len(raw) > 0 && raw[0] == '{'
+}
@@ -0,0 +1,174 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogoutTokenValidator(t *testing.T) {
+	signer := must(NewSignerRS(RS256, rsaPrivateKey256))
+	verifier := must(NewVerifierRS(RS256, rsaPublicKey256))
+
+	validator := &LogoutTokenValidator{
+		Issuer:   "https://op.example.com",
+		ClientID: "client",
+		MaxAge:   time.Minute,
+		Replay:   NewReplayGuard(100, 0),
+	}
+
+	testCases := []struct {
+		opts    []BuilderOption
+		claims  func(c map[string]any)
+		wantErr error
+	}{
+		{[]BuilderOption{WithType(TypeLogout)}, func(c map[string]any) {}, nil},
+		{[]BuilderOption{WithType("application/logout+jwt")}, func(c map[string]any) {}, nil},
+		{nil, func(c map[string]any) {}, ErrTypeMismatch},
+		{[]BuilderOption{WithType(TypeLogout)}, func(c map[string]any) { c["iss"] = "https://evil.example.com" }, ErrIssuerMismatch},
+		{[]BuilderOption{WithType(TypeLogout)}, func(c map[string]any) { delete(c, "iss") }, ErrMissingClaim},
+		{[]BuilderOption{WithType(TypeLogout)}, func(c map[string]any) { c["aud"] = "another" }, ErrAudienceMismatch},
+		{[]BuilderOption{WithType(TypeLogout)}, func(c map[string]any) { delete(c, "iat") }, ErrMissingClaim},
+		{[]BuilderOption{WithType(TypeLogout)}, func(c map[string]any) { delete(c, "exp") }, ErrMissingExpiresAt},
+		{[]BuilderOption{WithType(TypeLogout)}, func(c map[string]any) { delete(c, "jti") }, ErrMissingID},
+		{[]BuilderOption{WithType(TypeLogout)}, func(c map[string]any) { delete(c, "sid") }, nil},
+		{[]BuilderOption{WithType(TypeLogout)}, func(c map[string]any) { delete(c, "sid"); delete(c, "sub") }, ErrMissingClaim},
+		{[]BuilderOption{WithType(TypeLogout)}, func(c map[string]any) { c["nonce"] = "nonce" }, ErrNonceMismatch},
+		{[]BuilderOption{WithType(TypeLogout)}, func(c map[string]any) { c["events"] = map[string]any{} }, ErrMissingClaim},
+		{
+			[]BuilderOption{WithType(TypeLogout)},
+			func(c map[string]any) { c["events"] = map[string]any{BackChannelLogoutEvent: "yes"} },
+			ErrMissingClaim,
+		},
+		{
+			[]BuilderOption{WithType(TypeLogout)},
+			func(c map[string]any) { c["iat"] = time.Now().Add(-time.Hour).Unix() },
+			ErrTokenExpired,
+		},
+	}
+
+	for i, tc := range testCases {
+		claims := map[string]any{
+			"iss":    "https://op.example.com",
+			"aud":    "client",
+			"iat":    time.Now().Unix(),
+			"exp":    time.Now().Add(time.Minute).Unix(),
+			"jti":    "id-" + string(rune('a'+i)),
+			"sub":    "user",
+			"sid":    "session",
+			"events": map[string]any{BackChannelLogoutEvent: map[string]any{}},
+		}
+		tc.claims(claims)
+		token := must(NewBuilder(signer, tc.opts...).Build(claims))
+
+		_, err := validator.Parse(token.Bytes(), verifier)
+		mustEqual(t, errors.Is(err, tc.wantErr), true)
+	}
+
+	for _, validator := range []*LogoutTokenValidator{{ClientID: "client"}, {Issuer: "https://op.example.com"}} {
+		_, err := validator.Parse(nil, verifier)
+		mustEqual(t, err, ErrInvalidConfig)
+	}
+}
+
+func TestLogoutTokenValidatorUntyped(t *testing.T) {
+	signer := must(NewSignerHS(HS256, hsKey256))
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+	validator := &LogoutTokenValidator{Issuer: "op", ClientID: "client", AllowUntyped: true}
+
+	claims := &LogoutTokenClaims{
+		RegisteredClaims: RegisteredClaims{
+			ID: "id", Issuer: "op", Audience: Audience{"client"},
+			IssuedAt: NewNumericDate(time.Now()), ExpiresAt: NewNumericDate(time.Now().Add(time.Minute)),
+		},
+		Events:    map[string]json.RawMessage{BackChannelLogoutEvent: json.RawMessage(`{}`)},
+		SessionID: "session",
+	}
+
+	for _, opts := range [][]BuilderOption{nil, {WithType("")}} {
+		token := must(NewBuilder(signer, opts...).Build(claims))
+		_, err := validator.Parse(token.Bytes(), verifier)
+		mustOk(t, err)
+	}
+
+	token := must(NewBuilder(signer, WithType(TypeAccessToken)).Build(claims))
+	_, err := validator.Parse(token.Bytes(), verifier)
+	mustEqual(t, err, ErrTypeMismatch)
+}
+
+func TestLogoutHandler(t *testing.T) {
+	signer := must(NewSignerHS(HS256, hsKey256))
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+	validator := &LogoutTokenValidator{Issuer: "op", ClientID: "client"}
+
+	var loggedOut []string
+	handler := NewLogoutHandler(verifier, validator, func(ctx context.Context, claims *LogoutTokenClaims) error {
+		if claims.SessionID == "broken" {
+			return errors.New("cannot terminate session")
+		}
+		loggedOut = append(loggedOut, claims.SessionID)
+		return nil
+	})
+
+	newToken := func(sid string) string {
+		return must(NewBuilder(signer, WithType(TypeLogout)).Build(&LogoutTokenClaims{
+			RegisteredClaims: RegisteredClaims{
+				ID: sid, Issuer: "op", Audience: Audience{"client"},
+				IssuedAt: NewNumericDate(time.Now()), ExpiresAt: NewNumericDate(time.Now().Add(time.Minute)),
+			},
+			Events:    map[string]json.RawMessage{BackChannelLogoutEvent: json.RawMessage(`{}`)},
+			SessionID: sid,
+		})).String()
+	}
+
+	testCases := []struct {
+		method    string
+		form      url.Values
+		want      int
+		wantError string
+	}{
+		{http.MethodPost, url.Values{"logout_token": {newToken("session")}}, http.StatusOK, ""},
+		{http.MethodPost, url.Values{"logout_token": {newToken("broken")}}, http.StatusBadRequest, "logout_failed"},
+		{http.MethodPost, url.Values{"logout_token": {"not-a-token"}}, http.StatusBadRequest, "invalid_request"},
+		{http.MethodPost, url.Values{}, http.StatusBadRequest, "invalid_request"},
+		{http.MethodGet, url.Values{"logout_token": {newToken("session")}}, http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tc := range testCases {
+		r := httptest.NewRequest(tc.method, "/logout", strings.NewReader(tc.form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, r)
+		mustEqual(t, w.Code, tc.want)
+		mustEqual(t, w.Header().Get("Cache-Control"), "no-store")
+
+		if w.Code == http.StatusBadRequest {
+			var body map[string]string
+			mustOk(t, json.Unmarshal(w.Body.Bytes(), &body))
+			mustEqual(t, body["error"], tc.wantError)
+		}
+		mustEqual(t, strings.Contains(w.Body.String(), "cannot terminate session"), false)
+	}
+	mustEqual(t, loggedOut, []string{"session"})
+
+	// key fetch failure is not a token error.
+	handler = NewLogoutHandler(errVerifier{errors.New("keys unavailable")}, validator, nil)
+	r := httptest.NewRequest(http.MethodPost, "/logout", strings.NewReader(url.Values{"logout_token": {newToken("session")}}.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	mustEqual(t, w.Code, http.StatusBadRequest)
+	mustEqual(t, w.Body.String(), `{"error":"logout_failed"}`+"\n")
+}
+
+type errVerifier struct{ err error }
+
+func (errVerifier) Algorithm() Algorithm        { return HS256 }
+func (v errVerifier) Verify(token *Token) error { return v.err }
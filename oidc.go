@@ -0,0 +1,87 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// ProviderMetadata represents OpenID Provider metadata.
+// See: https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+type ProviderMetadata struct {
+	Issuer                           string      `json:"issuer"`
+	AuthorizationEndpoint            string      `json:"authorization_endpoint,omitempty"`
+	TokenEndpoint                    string      `json:"token_endpoint,omitempty"`
+	UserinfoEndpoint                 string      `json:"userinfo_endpoint,omitempty"`
+	JWKSURI                          string      `json:"jwks_uri"`
+	RegistrationEndpoint             string      `json:"registration_endpoint,omitempty"`
+	EndSessionEndpoint               string      `json:"end_session_endpoint,omitempty"`
+	ScopesSupported                  []string    `json:"scopes_supported,omitempty"`
+	ResponseTypesSupported           []string    `json:"response_types_supported,omitempty"`
+	SubjectTypesSupported            []string    `json:"subject_types_supported,omitempty"`
+	IDTokenSigningAlgValuesSupported []Algorithm `json:"id_token_signing_alg_values_supported,omitempty"`
+	ClaimsSupported                  []string    `json:"claims_supported,omitempty"`
+}
+
+// Provider is an OpenID Provider discovered with Discover.
+type Provider struct {
+	// Metadata of the provider.
+	Metadata ProviderMetadata
+
+	// Verifier verifies tokens with provider keys,
+	// restricted to `id_token_signing_alg_values_supported`.
+	Verifier *RemoteKeySet
+}
+
+// Discover fetches OpenID Provider metadata for an issuer and validates that `issuer` value matches.
+// If client is nil http.DefaultClient is used.
+// See: https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderConfig
+func Discover(ctx context.Context, client *http.Client, issuer string) (*Provider, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	url := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	body, err := httpGet(ctx, client, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta ProviderMetadata
+	if err := json.Unmarshal(body, &meta); err != nil {
+		return nil, err
+	}
+	switch {
+	case meta.Issuer != issuer:
+		return nil, ErrIssuerMismatch
+	case meta.JWKSURI == "":
+		return nil, ErrInvalidMetadata
+	}
+
+	algs := meta.IDTokenSigningAlgValuesSupported
+	if len(algs) == 0 {
+		// RS256 is a default value.
+		// See: https://openid.net/specs/openid-connect-core-1_0.html#IDToken
+		algs = []Algorithm{RS256}
+	}
+
+	p := &Provider{
+		Metadata: meta,
+		Verifier: NewRemoteKeySet(meta.JWKSURI, client, algs...),
+	}
+	return p, nil
+}
+
+// IDTokenValidator returns an IDTokenValidator for the provider and a given client.
+func (p *Provider) IDTokenValidator(clientID string) *IDTokenValidator {
+	return &IDTokenValidator{
+		Issuer:   p.Metadata.Issuer,
+		ClientID: clientID,
+	}
+}
+
+// ParseIDToken decodes an ID token, verifies it's signature with provider keys and validates claims.
+func (p *Provider) ParseIDToken(raw []byte, clientID string, params IDTokenParams) (*IDTokenClaims, error) {
+	return p.IDTokenValidator(clientID).Parse(raw, p.Verifier, params)
+}
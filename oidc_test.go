@@ -0,0 +1,67 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDiscover(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	jwks := &JWKS{Keys: []JWK{*must(NewJWK(ecdsaPublicKey256)), *must(NewJWK(rsaPublicKey256))}}
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		mustOk(t, json.NewEncoder(w).Encode(ProviderMetadata{
+			Issuer:                           srv.URL,
+			JWKSURI:                          srv.URL + "/jwks",
+			IDTokenSigningAlgValuesSupported: []Algorithm{ES256},
+		}))
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		mustOk(t, json.NewEncoder(w).Encode(jwks))
+	})
+
+	provider, err := Discover(context.Background(), srv.Client(), srv.URL)
+	mustOk(t, err)
+	mustEqual(t, provider.Metadata.JWKSURI, srv.URL+"/jwks")
+
+	now := time.Now()
+	claims := &IDTokenClaims{
+		RegisteredClaims: RegisteredClaims{
+			Issuer:    srv.URL,
+			Subject:   "user",
+			Audience:  Audience{"client"},
+			IssuedAt:  NewNumericDate(now),
+			ExpiresAt: NewNumericDate(now.Add(time.Minute)),
+		},
+		Nonce: "nonce",
+	}
+
+	esToken := must(NewBuilder(must(NewSignerES(ES256, ecdsaPrivateKey256))).Build(claims))
+	idClaims, err := provider.ParseIDToken(esToken.Bytes(), "client", IDTokenParams{Nonce: "nonce"})
+	mustOk(t, err)
+	mustEqual(t, idClaims.Subject, "user")
+
+	// RS256 is not in id_token_signing_alg_values_supported.
+	rsToken := must(NewBuilder(must(NewSignerRS(RS256, rsaPrivateKey256))).Build(claims))
+	_, err = provider.ParseIDToken(rsToken.Bytes(), "client", IDTokenParams{Nonce: "nonce"})
+	mustEqual(t, err, ErrKeyNotFound)
+}
+
+func TestDiscoverIssuerMismatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mustOk(t, json.NewEncoder(w).Encode(ProviderMetadata{
+			Issuer:  "https://evil.example.com",
+			JWKSURI: "https://evil.example.com/jwks",
+		}))
+	}))
+	defer srv.Close()
+
+	_, err := Discover(context.Background(), srv.Client(), srv.URL)
+	mustEqual(t, err, ErrIssuerMismatch)
+}
@@ -4,11 +4,29 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"sync"
 )
 
+// ParseOption is used to configure token parsing.
+type ParseOption func(*parseConfig)
+
+type parseConfig struct {
+	types []string
+}
+
+// WithAllowedTypes requires token `typ` header to be one of given values.
+// Comparison is case-insensitive and `application/` prefix is optional as RFC 7515 describes.
+// Empty value allows a token without `typ` header, AnyType allows every token.
+// Type is not checked if no values are given.
+// See: https://datatracker.ietf.org/doc/html/rfc8725#section-3.11
+func WithAllowedTypes(types ...string) ParseOption {
+	return func(c *parseConfig) { c.types = types }
+}
+
 // Parse decodes a token and verifies it's signature.
-func Parse(raw []byte, verifier Verifier) (*Token, error) {
-	token, err := ParseNoVerify(raw)
+func Parse(raw []byte, verifier Verifier, opts ...ParseOption) (*Token, error) {
+	token, err := ParseNoVerify(raw, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -19,8 +37,8 @@ func Parse(raw []byte, verifier Verifier) (*Token, error) {
 }
 
 // ParseClaims decodes a token claims and verifies it's signature.
-func ParseClaims(raw []byte, verifier Verifier, claims any) error {
-	token, err := Parse(raw, verifier)
+func ParseClaims(raw []byte, verifier Verifier, claims any, opts ...ParseOption) error {
+	token, err := Parse(raw, verifier, opts...)
 	if err != nil {
 		return err
 	}
@@ -29,57 +47,161 @@ func ParseClaims(raw []byte, verifier Verifier, claims any) error {
 
 // ParseNoVerify decodes a token from a raw bytes.
 // NOTE: Consider to use Parse with a verifier to verify token signature.
-func ParseNoVerify(raw []byte) (*Token, error) {
-	return parse(raw)
+func ParseNoVerify(raw []byte, opts ...ParseOption) (*Token, error) {
+	token, err := parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkParseOptions(token.header, opts); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// ParseInto is like Parse but decodes a token into dst reusing it's buffer.
+// Raw bytes are referenced by dst, they must not be modified while dst is used.
+// Previous dst content including decoded claims is overwritten, on error dst is reset.
+func ParseInto(dst *Token, raw []byte, verifier Verifier, opts ...ParseOption) error {
+	err := parseInto(dst, dst.buf, raw, true)
+	if err == nil {
+		err = checkParseOptions(dst.header, opts)
+	}
+	if err == nil {
+		err = verifier.Verify(dst)
+	}
+	if err != nil {
+		*dst = Token{buf: dst.buf[:0]}
+		return err
+	}
+	return nil
 }
 
+// VerifyBytes verifies a token signature without decoding claims.
+// Use it when only a signature check is needed, otherwise see Parse and ParseInto.
+func VerifyBytes(raw []byte, verifier Verifier, opts ...ParseOption) error {
+	token := tokenPool.Get().(*Token)
+	defer func() {
+		// don't keep a reference to the caller's raw bytes
+		*token = Token{buf: token.buf[:0]}
+		tokenPool.Put(token)
+	}()
+
+	if err := parseInto(token, token.buf, raw, false); err != nil {
+		return err
+	}
+	if err := checkParseOptions(token.header, opts); err != nil {
+		return err
+	}
+	return verifier.Verify(token)
+}
+
+var tokenPool = sync.Pool{
+	New: func() any { return &Token{} },
+}
+
+func checkParseOptions(header Header, opts []ParseOption) error {
+	if len(opts) == 0 {
+		return nil
+	}
+	var cfg parseConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if len(cfg.types) > 0 {
+		return checkType(header, cfg.types)
+	}
+	return nil
+}
+
+var (
+	errNotJSONObject = errors.New("must be a base64url encoded JSON object")
+	errNotThreeParts = errors.New("must have 3 parts separated by dots")
+)
+
 func parse(token []byte) (*Token, error) {
+	tk := &Token{}
+	if err := parseInto(tk, nil, token, true); err != nil {
+		return nil, err
+	}
+	return tk, nil
+}
+
+// parseInto decodes a token into tk, buf is reused for decoded parts if it has enough capacity.
+// Claims are not decoded if withClaims is false.
+func parseInto(tk *Token, buf, token []byte, withClaims bool) error {
 	// "eyJ" is `{"` which is begin of every JWT token.
 	// Quick check for the invalid input.
 	if !bytes.HasPrefix(token, []byte("eyJ")) {
-		return nil, ErrInvalidFormat
+		return &ParseError{Segment: "header", Err: errNotJSONObject}
 	}
 
 	dot1 := bytes.IndexByte(token, '.')
 	dot2 := bytes.LastIndexByte(token, '.')
 	if dot2 <= dot1 {
-		return nil, ErrInvalidFormat
+		return &ParseError{Err: errNotThreeParts}
 	}
 
-	buf := make([]byte, len(token))
-
-	headerN, err := b64Decode(buf, token[:dot1])
-	if err != nil {
-		return nil, ErrInvalidFormat
+	if cap(buf) < len(token) {
+		buf = make([]byte, len(token))
 	}
-	var header Header
-	if err := json.Unmarshal(buf[:headerN], &header); err != nil {
-		return nil, ErrInvalidFormat
+	buf = buf[:len(token)]
+
+	// predefined headers are not decoded, see Builder
+	header, ok := knownHeaders[string(token[:dot1])]
+	headerN := 0
+	if !ok {
+		var err error
+		headerN, err = b64Decode(buf, token[:dot1])
+		if err != nil {
+			return &ParseError{Segment: "header", Err: err}
+		}
+		var decoded Header
+		if err := json.Unmarshal(buf[:headerN], &decoded); err != nil {
+			return &ParseError{Segment: "header", Err: err}
+		}
+		header = decoded
 	}
 
-	claimsN, err := b64Decode(buf[headerN:], token[dot1+1:dot2])
-	if err != nil {
-		return nil, ErrInvalidFormat
+	var claims []byte
+	if withClaims {
+		claimsN, err := b64Decode(buf[headerN:], token[dot1+1:dot2])
+		if err != nil {
+			return &ParseError{Segment: "claims", Err: err}
+		}
+		claims = buf[headerN : headerN+claimsN]
 	}
-	claims := buf[headerN : headerN+claimsN]
 
-	signN, err := b64Decode(buf[headerN+claimsN:], token[dot2+1:])
+	signatureAt := headerN + len(claims)
+	signN, err := b64Decode(buf[signatureAt:], token[dot2+1:])
 	if err != nil {
-		return nil, ErrInvalidFormat
+		return &ParseError{Segment: "signature", Err: err}
 	}
-	signature := buf[headerN+claimsN : headerN+claimsN+signN]
+	signature := buf[signatureAt : signatureAt+signN]
 
-	tk := &Token{
+	*tk = Token{
 		raw:       token,
 		dot1:      dot1,
 		dot2:      dot2,
 		signature: signature,
 		header:    header,
 		claims:    claims,
+		buf:       buf,
 	}
-	return tk, nil
+	return nil
 }
 
+// knownHeaders are decoded predefined headers.
+var knownHeaders = func() map[string]Header {
+	headers := make(map[string]Header, len(predefinedHeaders)+len(predefinedTypedHeaders))
+	for alg, h := range predefinedHeaders {
+		headers[h] = Header{Algorithm: alg, Type: TypeJWT}
+	}
+	for key, h := range predefinedTypedHeaders {
+		headers[h] = Header{Algorithm: key.alg, Type: key.typ}
+	}
+	return headers
+}()
+
 func b64Decode(dst, src []byte) (n int, err error) {
 	return base64.RawURLEncoding.Decode(dst, src)
 }
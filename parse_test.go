@@ -1,6 +1,9 @@
 package jwt
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -54,24 +57,156 @@ func TestParseAnotherAlgorithm(t *testing.T) {
 	mustEqual(t, err, ErrAlgorithmMismatch)
 }
 
+func TestParseAllowedTypes(t *testing.T) {
+	signer := must(NewSignerHS(HS256, hsKey256))
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+
+	testCases := []struct {
+		typ     string
+		types   []string
+		wantErr error
+	}{
+		{"at+jwt", []string{"at+jwt"}, nil},
+		{"application/AT+JWT", []string{"at+jwt"}, nil},
+		{"at+jwt", []string{"application/at+jwt"}, nil},
+		{"application/at+jwt", []string{"Application/AT+JWT"}, nil},
+		{"application/jwt", []string{"application/at+jwt"}, ErrTypeMismatch},
+		{"JWT", []string{"at+jwt"}, ErrTypeMismatch},
+		{"JWT", []string{"at+jwt", "JWT"}, nil},
+		{"", []string{"at+jwt"}, ErrTypeMismatch},
+		{"", []string{"at+jwt", ""}, nil},
+		{"logout+jwt", []string{AnyType}, nil},
+	}
+
+	for _, tc := range testCases {
+		token := must(NewBuilder(signer, WithType(tc.typ)).Build(&RegisteredClaims{}))
+
+		_, err := Parse(token.Bytes(), verifier, WithAllowedTypes(tc.types...))
+		mustEqual(t, err, tc.wantErr)
+
+		err = NewTypeValidator(tc.types...).Validate(context.Background(), token)
+		mustEqual(t, err, tc.wantErr)
+	}
+
+	token := must(NewBuilder(signer, WithType("at+jwt")).Build(&RegisteredClaims{}))
+	_, err := Parse(token.Bytes(), verifier)
+	mustOk(t, err)
+}
+
+func TestParseInto(t *testing.T) {
+	signer := must(NewSignerHS(HS256, hsKey256))
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+
+	first := must(NewBuilder(signer).Build(&RegisteredClaims{Subject: "first"}))
+	second := must(NewBuilder(signer, WithKeyID("kid")).Build(&RegisteredClaims{Subject: "second"}))
+
+	var token Token
+	mustOk(t, ParseInto(&token, first.Bytes(), verifier))
+	mustEqual(t, token.Header(), first.Header())
+	mustEqual(t, string(token.Claims()), `{"sub":"first"}`)
+	mustEqual(t, token.Signature(), first.Signature())
+
+	mustOk(t, ParseInto(&token, second.Bytes(), verifier))
+	mustEqual(t, token.Header(), second.Header())
+	mustEqual(t, string(token.Claims()), `{"sub":"second"}`)
+	mustEqual(t, token.String(), second.String())
+
+	err := ParseInto(&token, first.Bytes(), verifier, WithAllowedTypes(TypeAccessToken))
+	mustEqual(t, err, ErrTypeMismatch)
+	mustEqual(t, token.isValid(), false)
+
+	err = ParseInto(&token, first.Bytes(), must(NewVerifierHS(HS256, []byte("another-key"))))
+	mustEqual(t, err, ErrInvalidSignature)
+	mustEqual(t, token.isValid(), false)
+
+	err = ParseInto(&token, []byte("eyJ.bad"), verifier)
+	mustEqual(t, errors.Is(err, ErrInvalidFormat), true)
+
+	raw := first.Bytes()
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = ParseInto(&token, raw, nopVerifier{})
+	})
+	mustEqual(t, allocs, 0.0)
+}
+
+func TestVerifyBytes(t *testing.T) {
+	signer := must(NewSignerHS(HS256, hsKey256))
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+
+	testCases := []struct {
+		token    string
+		verifier Verifier
+		opts     []ParseOption
+		wantErr  error
+	}{
+		{
+			must(NewBuilder(signer).Build(simplePayload)).String(),
+			verifier, nil, nil,
+		},
+		{
+			must(NewBuilder(signer, WithType(TypeAccessToken), WithKeyID("kid")).Build(simplePayload)).String(),
+			verifier, []ParseOption{WithAllowedTypes(TypeAccessToken)}, nil,
+		},
+		{
+			must(NewBuilder(signer).Build(simplePayload)).String(),
+			verifier, []ParseOption{WithAllowedTypes(TypeAccessToken)}, ErrTypeMismatch,
+		},
+		{
+			must(NewBuilder(signer).Build(simplePayload)).String(),
+			must(NewVerifierHS(HS256, []byte("another-key"))), nil, ErrInvalidSignature,
+		},
+		{
+			must(NewBuilder(signer).Build(simplePayload)).String(),
+			must(NewVerifierHS(HS512, hsKey256)), nil, ErrAlgorithmMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		err := VerifyBytes([]byte(tc.token), tc.verifier, tc.opts...)
+		mustEqual(t, err, tc.wantErr)
+	}
+
+	err := VerifyBytes([]byte("eyJhbGciOiJIUzI1NiJ9.e30"), verifier)
+	mustEqual(t, errors.Is(err, ErrInvalidFormat), true)
+}
+
 func TestParseMalformed(t *testing.T) {
 	testCases := []struct {
-		token string
+		token   string
+		segment string
 	}{
-		{`xyz.xyz`},
-		{`eyJ.xyz`},
-		{`eyJ!.x!yz.e30`},
-		{`eyJ.xyz.xyz`},
-		{`eyJhIjoxMjN9.x!yz.e30`}, // `e30` is JSON `{}` in base64.
-		{`eyJhIjoxMjN9.e30.x!yz`},
+		{`xyz.xyz`, "header"},
+		{`eyJ.xyz`, ""},
+		{`eyJ!.x!yz.e30`, "header"},
+		{`eyJ.xyz.xyz`, "header"},
+		{`eyJhIjoxMjN9.x!yz.e30`, "claims"}, // `e30` is JSON `{}` in base64.
+		{`eyJhIjoxMjN9.e30.x!yz`, "signature"},
+		{`eyJhIjoxMjN9.e30.e30.e30`, "claims"},
 	}
 
 	for _, tc := range testCases {
 		_, err := Parse([]byte(tc.token), nopVerifier{})
-		mustEqual(t, err, ErrInvalidFormat)
+		mustEqual(t, errors.Is(err, ErrInvalidFormat), true)
+
+		var parseErr *ParseError
+		mustEqual(t, errors.As(err, &parseErr), true)
+		mustEqual(t, parseErr.Segment, tc.segment)
+		mustEqual(t, parseErr.Err != nil, true)
 	}
 }
 
+func TestParseErrorMessage(t *testing.T) {
+	_, err := Parse([]byte(`eyJhIjoxMjN9.e30.x!yz`), nopVerifier{})
+	mustEqual(t, err.Error(), "token format is not valid: signature: illegal base64 data at input byte 1")
+
+	_, err = Parse([]byte(`eyJhIjoxMjN9`), nopVerifier{})
+	mustEqual(t, err.Error(), "token format is not valid: must have 3 parts separated by dots")
+
+	_, err = Parse([]byte(bytesToBase64([]byte(`{"alg":1}`))+".e30.e30"), nopVerifier{})
+	var typeErr *json.UnmarshalTypeError
+	mustEqual(t, errors.As(err, &typeErr), true)
+}
+
 type nopVerifier struct{}
 
 func (nopVerifier) Algorithm() Algorithm      { return "nop" }
@@ -0,0 +1,7 @@
+//go:build race
+
+package jwt
+
+// raceEnabled is true in race builds, sync.Pool drops items randomly there,
+// so allocation counts of pooled code are not stable.
+const raceEnabled = true
@@ -0,0 +1,291 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RefreshClaims represents claims of a refresh token issued by Refresher.
+type RefreshClaims struct {
+	RegisteredClaims
+
+	// Family claim identifies a chain of rotated refresh tokens.
+	Family string `json:"fam"`
+}
+
+// RefreshToken is a refresh token state kept in RefreshStore.
+type RefreshToken struct {
+	ID        string
+	Family    string
+	Subject   string
+	ExpiresAt time.Time
+	Used      bool
+	Revoked   bool
+}
+
+// RefreshStore keeps refresh tokens and their families.
+type RefreshStore interface {
+	// Save stores a new refresh token.
+	// Returns ErrRefreshTokenExists if a token with the same id is already stored.
+	Save(ctx context.Context, token RefreshToken) error
+
+	// Use atomically marks a refresh token as used and returns it's state before the call.
+	// Returns ErrRefreshTokenNotFound if token is unknown or has another family or subject,
+	// such token must not be marked as used.
+	Use(ctx context.Context, id, family, subject string) (RefreshToken, error)
+
+	// RevokeFamily revokes all refresh tokens of a given family.
+	RevokeFamily(ctx context.Context, family string) error
+}
+
+// TokenPair is a pair of access and refresh tokens.
+type TokenPair struct {
+	AccessToken  *Token
+	RefreshToken *Token
+}
+
+// RefresherConfig is used to create a Refresher.
+type RefresherConfig struct {
+	// AccessBuilder builds access tokens.
+	AccessBuilder *Builder
+
+	// RefreshBuilder builds refresh tokens.
+	// Refresh tokens are never sent to resource servers, so it can be a separate key.
+	RefreshBuilder *Builder
+
+	// RefreshVerifier verifies refresh tokens built by RefreshBuilder.
+	RefreshVerifier Verifier
+
+	// Store keeps refresh tokens state.
+	Store RefreshStore
+
+	// AccessTTL is a lifetime of access tokens.
+	AccessTTL time.Duration
+
+	// RefreshTTL is a lifetime of refresh tokens.
+	RefreshTTL time.Duration
+}
+
+// Refresher issues access and refresh token pairs and rotates refresh token on each use.
+// When an already used refresh token is presented again the whole token family is revoked.
+// See: https://datatracker.ietf.org/doc/html/draft-ietf-oauth-v2-1#section-4.3.1
+// Safe to use concurrently.
+type Refresher struct {
+	cfg RefresherConfig
+	now func() time.Time
+}
+
+// NewRefresher returns new instance of Refresher.
+func NewRefresher(cfg RefresherConfig) (*Refresher, error) {
+	switch {
+	case cfg.AccessBuilder == nil, cfg.RefreshBuilder == nil, cfg.RefreshVerifier == nil, cfg.Store == nil:
+		return nil, ErrInvalidConfig
+	case cfg.AccessTTL <= 0, cfg.RefreshTTL <= 0:
+		return nil, ErrInvalidConfig
+	}
+	return &Refresher{cfg: cfg, now: time.Now}, nil
+}
+
+// Issue creates a new token pair for a subject and starts a new token family.
+func (r *Refresher) Issue(ctx context.Context, subject string) (*TokenPair, error) {
+	family, err := newTokenID()
+	if err != nil {
+		return nil, err
+	}
+	return r.issue(ctx, subject, family)
+}
+
+// Refresh verifies a refresh token, marks it as used and issues a new token pair in the same family.
+// Reuse of a refresh token revokes the family and returns ErrRefreshTokenReused.
+func (r *Refresher) Refresh(ctx context.Context, raw []byte) (*TokenPair, error) {
+	var claims RefreshClaims
+	if err := ParseClaims(raw, r.cfg.RefreshVerifier, &claims); err != nil {
+		return nil, err
+	}
+	switch {
+	case claims.ID == "":
+		return nil, ErrMissingID
+	case claims.Family == "":
+		return nil, ErrRefreshTokenNotFound
+	case !claims.IsValidExpiresAt(r.now()):
+		return nil, ErrTokenExpired
+	}
+
+	stored, err := r.cfg.Store.Use(ctx, claims.ID, claims.Family, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case stored.Revoked:
+		return nil, ErrTokenRevoked
+	case stored.Used:
+		if err := r.cfg.Store.RevokeFamily(ctx, stored.Family); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	return r.issue(ctx, stored.Subject, stored.Family)
+}
+
+// Revoke revokes a token family of a given refresh token, used on logout.
+func (r *Refresher) Revoke(ctx context.Context, raw []byte) error {
+	var claims RefreshClaims
+	if err := ParseClaims(raw, r.cfg.RefreshVerifier, &claims); err != nil {
+		return err
+	}
+	if claims.Family == "" {
+		return ErrRefreshTokenNotFound
+	}
+	return r.cfg.Store.RevokeFamily(ctx, claims.Family)
+}
+
+func (r *Refresher) issue(ctx context.Context, subject, family string) (*TokenPair, error) {
+	now := r.now()
+
+	accessID, err := newTokenID()
+	if err != nil {
+		return nil, err
+	}
+	access, err := r.cfg.AccessBuilder.Build(&RegisteredClaims{
+		ID:        accessID,
+		Subject:   subject,
+		IssuedAt:  NewNumericDate(now),
+		ExpiresAt: NewNumericDate(now.Add(r.cfg.AccessTTL)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refreshID, err := newTokenID()
+	if err != nil {
+		return nil, err
+	}
+	refreshExp := now.Add(r.cfg.RefreshTTL)
+	refresh, err := r.cfg.RefreshBuilder.Build(&RefreshClaims{
+		RegisteredClaims: RegisteredClaims{
+			ID:        refreshID,
+			Subject:   subject,
+			IssuedAt:  NewNumericDate(now),
+			ExpiresAt: NewNumericDate(refreshExp),
+		},
+		Family: family,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.cfg.Store.Save(ctx, RefreshToken{
+		ID:        refreshID,
+		Family:    family,
+		Subject:   subject,
+		ExpiresAt: refreshExp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{AccessToken: access, RefreshToken: refresh}, nil
+}
+
+// MemoryRefreshStore is an in-memory RefreshStore.
+// Expired tokens are removed periodically.
+// Safe to use concurrently.
+type MemoryRefreshStore struct {
+	mu        sync.Mutex
+	tokens    map[string]*RefreshToken
+	families  map[string][]string
+	nextSweep int
+	now       func() time.Time
+}
+
+// NewMemoryRefreshStore returns new instance of MemoryRefreshStore.
+func NewMemoryRefreshStore() *MemoryRefreshStore {
+	return &MemoryRefreshStore{
+		tokens:    map[string]*RefreshToken{},
+		families:  map[string][]string{},
+		nextSweep: minRevocationSweep,
+		now:       time.Now,
+	}
+}
+
+// Save implements RefreshStore interface.
+func (s *MemoryRefreshStore) Save(ctx context.Context, token RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.tokens) >= s.nextSweep {
+		s.sweep()
+	}
+	if _, ok := s.tokens[token.ID]; ok {
+		return ErrRefreshTokenExists
+	}
+
+	// family is revoked, new tokens of the family are revoked too.
+	for _, id := range s.families[token.Family] {
+		if t, ok := s.tokens[id]; ok && t.Revoked {
+			token.Revoked = true
+			break
+		}
+	}
+
+	s.tokens[token.ID] = &token
+	s.families[token.Family] = append(s.families[token.Family], token.ID)
+	return nil
+}
+
+// Use implements RefreshStore interface.
+func (s *MemoryRefreshStore) Use(ctx context.Context, id, family, subject string) (RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.tokens[id]
+	if !ok || !constTimeEqual(token.Family, family) || !constTimeEqual(token.Subject, subject) {
+		return RefreshToken{}, ErrRefreshTokenNotFound
+	}
+	prev := *token
+	token.Used = true
+	return prev, nil
+}
+
+// RevokeFamily implements RefreshStore interface.
+func (s *MemoryRefreshStore) RevokeFamily(ctx context.Context, family string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range s.families[family] {
+		if token, ok := s.tokens[id]; ok {
+			token.Revoked = true
+		}
+	}
+	return nil
+}
+
+// sweep removes expired tokens, must be called under a lock.
+func (s *MemoryRefreshStore) sweep() {
+	now := s.now()
+	for family, ids := range s.families {
+		alive := ids[:0]
+		for _, id := range ids {
+			token, ok := s.tokens[id]
+			switch {
+			case !ok:
+				// already removed, skip a stale id
+			case token.ExpiresAt.After(now):
+				alive = append(alive, id)
+			default:
+				delete(s.tokens, id)
+			}
+		}
+		if len(alive) == 0 {
+			delete(s.families, family)
+		} else {
+			s.families[family] = alive
+		}
+	}
+
+	s.nextSweep = 2 * len(s.tokens)
+	if s.nextSweep < minRevocationSweep {
+		s.nextSweep = minRevocationSweep
+	}
+}
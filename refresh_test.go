@@ -0,0 +1,191 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRefresher(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRefresher(t)
+
+	pair, err := r.Issue(ctx, "user")
+	mustOk(t, err)
+
+	var access RegisteredClaims
+	mustOk(t, pair.AccessToken.DecodeClaims(&access))
+	mustEqual(t, access.Subject, "user")
+
+	var refresh RefreshClaims
+	mustOk(t, pair.RefreshToken.DecodeClaims(&refresh))
+	mustEqual(t, refresh.Subject, "user")
+
+	rotated, err := r.Refresh(ctx, pair.RefreshToken.Bytes())
+	mustOk(t, err)
+
+	var rotatedClaims RefreshClaims
+	mustOk(t, rotated.RefreshToken.DecodeClaims(&rotatedClaims))
+	mustEqual(t, rotatedClaims.Family, refresh.Family)
+	mustEqual(t, rotatedClaims.ID == refresh.ID, false)
+
+	// reuse of the first refresh token revokes the whole family.
+	_, err = r.Refresh(ctx, pair.RefreshToken.Bytes())
+	mustEqual(t, err, ErrRefreshTokenReused)
+
+	_, err = r.Refresh(ctx, rotated.RefreshToken.Bytes())
+	mustEqual(t, err, ErrTokenRevoked)
+
+	// another family is not affected.
+	other, err := r.Issue(ctx, "user")
+	mustOk(t, err)
+	_, err = r.Refresh(ctx, other.RefreshToken.Bytes())
+	mustOk(t, err)
+}
+
+func TestRefresherBadTokens(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRefresher(t)
+	r.cfg.AccessBuilder = r.cfg.RefreshBuilder
+
+	pair, err := r.Issue(ctx, "user")
+	mustOk(t, err)
+
+	// access token is not a refresh token even when signed with the same key.
+	_, err = r.Refresh(ctx, pair.AccessToken.Bytes())
+	mustEqual(t, err, ErrRefreshTokenNotFound)
+
+	// unknown refresh token.
+	unknown := must(r.cfg.RefreshBuilder.Build(&RefreshClaims{
+		RegisteredClaims: RegisteredClaims{ID: "unknown"},
+		Family:           "family",
+	}))
+	_, err = r.Refresh(ctx, unknown.Bytes())
+	mustEqual(t, err, ErrRefreshTokenNotFound)
+
+	// known id with another family or subject doesn't burn the token.
+	var claims RefreshClaims
+	mustOk(t, pair.RefreshToken.DecodeClaims(&claims))
+	for _, mismatch := range []*RefreshClaims{
+		{RegisteredClaims: RegisteredClaims{ID: claims.ID, Subject: claims.Subject}, Family: "another"},
+		{RegisteredClaims: RegisteredClaims{ID: claims.ID, Subject: "another"}, Family: claims.Family},
+	} {
+		_, err = r.Refresh(ctx, must(r.cfg.RefreshBuilder.Build(mismatch)).Bytes())
+		mustEqual(t, err, ErrRefreshTokenNotFound)
+	}
+	_, err = r.Refresh(ctx, pair.RefreshToken.Bytes())
+	mustOk(t, err)
+
+	// expired refresh token.
+	r.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, err = r.Refresh(ctx, pair.RefreshToken.Bytes())
+	mustEqual(t, err, ErrTokenExpired)
+}
+
+func TestRefresherRevoke(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRefresher(t)
+
+	pair, err := r.Issue(ctx, "user")
+	mustOk(t, err)
+
+	mustOk(t, r.Revoke(ctx, pair.RefreshToken.Bytes()))
+
+	_, err = r.Refresh(ctx, pair.RefreshToken.Bytes())
+	mustEqual(t, err, ErrTokenRevoked)
+}
+
+func TestRefresherConcurrently(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRefresher(t)
+
+	pair, err := r.Issue(ctx, "user")
+	mustOk(t, err)
+
+	const workers = 8
+	errs := make(chan error, workers)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			_, err := r.Refresh(ctx, pair.RefreshToken.Bytes())
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	accepted := 0
+	for err := range errs {
+		if err == nil {
+			accepted++
+		}
+	}
+	mustEqual(t, accepted, 1)
+}
+
+func TestNewRefresherBadConfig(t *testing.T) {
+	_, err := NewRefresher(RefresherConfig{})
+	mustEqual(t, err, ErrInvalidConfig)
+}
+
+func TestMemoryRefreshStoreSweep(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	store := NewMemoryRefreshStore()
+	store.now = func() time.Time { return now }
+
+	for i := 0; i < minRevocationSweep; i++ {
+		mustOk(t, store.Save(ctx, RefreshToken{
+			ID:        string(rune('a' + i)),
+			Family:    "expired",
+			ExpiresAt: now.Add(-time.Second),
+		}))
+	}
+	mustOk(t, store.Save(ctx, RefreshToken{ID: "alive", Family: "alive", ExpiresAt: now.Add(time.Hour)}))
+
+	mustEqual(t, len(store.tokens), 1)
+	mustEqual(t, len(store.families), 1)
+}
+
+func TestMemoryRefreshStoreDuplicateID(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	store := NewMemoryRefreshStore()
+	store.now = func() time.Time { return now }
+
+	mustOk(t, store.Save(ctx, RefreshToken{ID: "id", Family: "first", ExpiresAt: now.Add(-time.Second)}))
+	mustEqual(t, store.Save(ctx, RefreshToken{ID: "id", Family: "first", ExpiresAt: now.Add(time.Hour)}), ErrRefreshTokenExists)
+	mustEqual(t, store.Save(ctx, RefreshToken{ID: "id", Family: "second", ExpiresAt: now.Add(time.Hour)}), ErrRefreshTokenExists)
+
+	// used token can't be saved again to reset it's state
+	_, err := store.Use(ctx, "id", "first", "")
+	mustOk(t, err)
+	mustEqual(t, store.Save(ctx, RefreshToken{ID: "id", Family: "first"}), ErrRefreshTokenExists)
+	mustEqual(t, store.families, map[string][]string{"first": {"id"}})
+
+	// stale ids in families index are skipped
+	store.families["second"] = []string{"id", "unknown"}
+	store.sweep()
+	mustEqual(t, len(store.tokens), 0)
+	mustEqual(t, len(store.families), 0)
+}
+
+func newTestRefresher(t *testing.T) *Refresher {
+	t.Helper()
+	r, err := NewRefresher(RefresherConfig{
+		AccessBuilder:   NewBuilder(must(NewSignerHS(HS256, hsKey256))),
+		RefreshBuilder:  NewBuilder(must(NewSignerHS(HS512, hsKey512))),
+		RefreshVerifier: must(NewVerifierHS(HS512, hsKey512)),
+		Store:           NewMemoryRefreshStore(),
+		AccessTTL:       time.Minute,
+		RefreshTTL:      time.Hour,
+	})
+	mustOk(t, err)
+	return r
+}
@@ -0,0 +1,129 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ReplayGuard rejects a second use of a token with the same `jti` claim.
+// Each accepted id is remembered until token expiration plus leeway.
+// Safe to use concurrently.
+type ReplayGuard struct {
+	shards []replayShard
+	leeway time.Duration
+	now    func() time.Time
+}
+
+type replayShard struct {
+	mu       sync.Mutex
+	entries  map[string]time.Time
+	capacity int
+}
+
+const replayShards = 32
+
+// NewReplayGuard returns new instance of ReplayGuard that keeps at most capacity ids.
+// Capacity is split between up to 32 shards by id hash, so a shard can be full before the whole guard is.
+// When a shard is full and no ids in it are expired, new ids are rejected with ErrReplayCacheFull.
+func NewReplayGuard(capacity int, leeway time.Duration) *ReplayGuard {
+	if capacity < 1 {
+		capacity = 1
+	}
+	shards := replayShards
+	if capacity < shards {
+		shards = capacity
+	}
+
+	g := &ReplayGuard{
+		shards: make([]replayShard, shards),
+		leeway: leeway,
+		now:    time.Now,
+	}
+	for i := range g.shards {
+		perShard := capacity / shards
+		if i < capacity%shards {
+			perShard++
+		}
+		g.shards[i] = replayShard{
+			entries:  map[string]time.Time{},
+			capacity: perShard,
+		}
+	}
+	return g
+}
+
+// Validate implements Validator interface.
+// Token must have `jti` and `exp` claims.
+func (g *ReplayGuard) Validate(ctx context.Context, token *Token) error {
+	var claims RegisteredClaims
+	if err := token.DecodeRegisteredClaims(&claims); err != nil {
+		return err
+	}
+	switch {
+	case claims.ID == "":
+		return ErrMissingID
+	case claims.ExpiresAt == nil:
+		return ErrMissingExpiresAt
+	default:
+		return g.Use(claims.ID, claims.ExpiresAt.Time)
+	}
+}
+
+// Use records a given id till exp (plus leeway).
+// Returns ErrTokenReplayed if the id was already used and isn't expired yet.
+func (g *ReplayGuard) Use(jti string, exp time.Time) error {
+	now := g.now()
+	until := exp.Add(g.leeway)
+	if !until.After(now) {
+		return ErrTokenExpired
+	}
+
+	shard := &g.shards[shardIndex(jti, len(g.shards))]
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	if seen, ok := shard.entries[jti]; ok && seen.After(now) {
+		return ErrTokenReplayed
+	}
+
+	if len(shard.entries) >= shard.capacity {
+		shard.sweep(now)
+		if len(shard.entries) >= shard.capacity {
+			return ErrReplayCacheFull
+		}
+	}
+	shard.entries[jti] = until
+	return nil
+}
+
+// Len returns number of remembered ids.
+func (g *ReplayGuard) Len() int {
+	n := 0
+	for i := range g.shards {
+		shard := &g.shards[i]
+		shard.mu.Lock()
+		n += len(shard.entries)
+		shard.mu.Unlock()
+	}
+	return n
+}
+
+// sweep removes expired ids, must be called under a lock.
+func (s *replayShard) sweep(now time.Time) {
+	for jti, until := range s.entries {
+		if !until.After(now) {
+			delete(s.entries, jti)
+		}
+	}
+}
+
+// shardIndex is an inlined FNV-1a hash to avoid allocations.
+func shardIndex(s string, n int) int {
+	h := uint32(2166136261)
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= 16777619
+	}
+	return int(h % uint32(n))
+}
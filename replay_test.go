@@ -0,0 +1,112 @@
+package jwt
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReplayGuard(t *testing.T) {
+	ctx := context.Background()
+	signer := must(NewSignerHS(HS256, hsKey256))
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+	builder := NewBuilder(signer)
+
+	guard := NewReplayGuard(1000, time.Minute)
+	exp := NewNumericDate(time.Now().Add(time.Minute))
+
+	token := must(builder.Build(&RegisteredClaims{ID: "one-time", ExpiresAt: exp}))
+
+	_, err := ParseAndValidate(ctx, token.Bytes(), verifier, guard)
+	mustOk(t, err)
+
+	_, err = ParseAndValidate(ctx, token.Bytes(), verifier, guard)
+	mustEqual(t, err, ErrTokenReplayed)
+
+	noID := must(builder.Build(&RegisteredClaims{ExpiresAt: exp}))
+	_, err = ParseAndValidate(ctx, noID.Bytes(), verifier, guard)
+	mustEqual(t, err, ErrMissingID)
+
+	noExp := must(builder.Build(&RegisteredClaims{ID: "no-exp"}))
+	_, err = ParseAndValidate(ctx, noExp.Bytes(), verifier, guard)
+	mustEqual(t, err, ErrMissingExpiresAt)
+}
+
+func TestReplayGuardExpiration(t *testing.T) {
+	now := time.Now()
+	guard := NewReplayGuard(10, time.Minute)
+	guard.now = func() time.Time { return now }
+
+	mustEqual(t, guard.Use("expired", now.Add(-2*time.Minute)), ErrTokenExpired)
+
+	// within leeway
+	mustOk(t, guard.Use("leeway", now.Add(-30*time.Second)))
+	mustEqual(t, guard.Use("leeway", now.Add(-30*time.Second)), ErrTokenReplayed)
+
+	// after leeway the id is forgotten, but token itself is expired.
+	now = now.Add(time.Minute)
+	mustEqual(t, guard.Use("leeway", now.Add(-2*time.Minute)), ErrTokenExpired)
+}
+
+func TestReplayGuardFull(t *testing.T) {
+	now := time.Now()
+	guard := NewReplayGuard(1, 0)
+	guard.now = func() time.Time { return now }
+
+	first, second := "id-0", "id-1"
+
+	mustOk(t, guard.Use(first, now.Add(time.Second)))
+	mustEqual(t, guard.Use(second, now.Add(time.Second)), ErrReplayCacheFull)
+
+	now = now.Add(2 * time.Second)
+	mustOk(t, guard.Use(second, now.Add(time.Second)))
+	mustEqual(t, guard.Len(), 1)
+}
+
+func TestReplayGuardCapacity(t *testing.T) {
+	exp := time.Now().Add(time.Minute)
+
+	for _, capacity := range []int{1, 31, 32, 33, 100} {
+		guard := NewReplayGuard(capacity, 0)
+
+		accepted := 0
+		for i := 0; i < 100*capacity+1000; i++ {
+			if guard.Use("id-"+strconv.Itoa(i), exp) == nil {
+				accepted++
+			}
+		}
+		mustEqual(t, accepted, capacity)
+		mustEqual(t, guard.Len(), capacity)
+	}
+}
+
+func TestReplayGuardConcurrently(t *testing.T) {
+	guard := NewReplayGuard(100, 0)
+	exp := time.Now().Add(time.Minute)
+
+	const workers = 16
+	results := make(chan error, workers)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			results <- guard.Use("same-id", exp)
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	accepted := 0
+	for err := range results {
+		if err == nil {
+			accepted++
+			continue
+		}
+		mustEqual(t, err, ErrTokenReplayed)
+	}
+	mustEqual(t, accepted, 1)
+}
@@ -0,0 +1,158 @@
+package jwt
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// RequestObjectClaims represents claims of a JWT-Secured Authorization Request object.
+// See: https://datatracker.ietf.org/doc/html/rfc9101#section-4
+type RequestObjectClaims struct {
+	RegisteredClaims
+
+	ClientID            string `json:"client_id"`
+	ResponseType        string `json:"response_type,omitempty"`
+	RedirectURI         string `json:"redirect_uri,omitempty"`
+	Scope               string `json:"scope,omitempty"`
+	State               string `json:"state,omitempty"`
+	Nonce               string `json:"nonce,omitempty"`
+	ResponseMode        string `json:"response_mode,omitempty"`
+	CodeChallenge       string `json:"code_challenge,omitempty"`
+	CodeChallengeMethod string `json:"code_challenge_method,omitempty"`
+	Prompt              string `json:"prompt,omitempty"`
+	MaxAge              *int64 `json:"max_age,omitempty"`
+}
+
+// RequestObjectBuilder is used to create authorization request objects.
+// Safe to use concurrently.
+type RequestObjectBuilder struct {
+	builder *Builder
+}
+
+// NewRequestObjectBuilder returns new instance of RequestObjectBuilder.
+// Header `typ` is always set to `oauth-authz-req+jwt`.
+func NewRequestObjectBuilder(signer Signer, opts ...BuilderOption) *RequestObjectBuilder {
+	opts = append(opts[:len(opts):len(opts)], WithType(TypeAuthzRequest))
+	return &RequestObjectBuilder{
+		builder: NewBuilder(signer, opts...),
+	}
+}
+
+// Build builds a request object.
+// Claims must have `client_id` and `aud`, `iss` is set to `client_id` if empty.
+func (b *RequestObjectBuilder) Build(claims *RequestObjectClaims) (*Token, error) {
+	switch {
+	case claims.ClientID == "":
+		return nil, missingClaim("client_id")
+	case len(claims.Audience) == 0:
+		return nil, missingClaim("aud")
+	case claims.Issuer != "" && claims.Issuer != claims.ClientID:
+		return nil, ErrIssuerMismatch
+	}
+
+	if claims.Issuer == "" {
+		c := *claims
+		c.Issuer = c.ClientID
+		claims = &c
+	}
+	return b.builder.Build(claims)
+}
+
+// RequestObjectValidator validates authorization request objects, used by authorization servers.
+// See: https://datatracker.ietf.org/doc/html/rfc9101#section-6
+type RequestObjectValidator struct {
+	// Issuer is an authorization server issuer identifier, expected in `aud` claim.
+	Issuer string
+
+	// RequireType rejects request objects without `typ: oauth-authz-req+jwt` header.
+	// Request objects with another `typ` are always rejected.
+	RequireType bool
+
+	// Types are allowed `typ` header values, overrides default types and RequireType if not empty.
+	Types []string
+
+	// Leeway is an allowed clock skew for time claims.
+	Leeway time.Duration
+
+	// Now returns current time, time.Now is used if nil.
+	Now func() time.Time
+}
+
+// Parse verifies a request object of a given client and maps it's claims to authorization request parameters.
+// Values that aren't strings are encoded as JSON, except numbers.
+// Registered JWT claims `iss`, `aud`, `exp`, `nbf`, `iat` and `jti` are not parameters and are omitted.
+// Request object with `request` or `request_uri` claim is rejected with ErrUnexpectedClaim.
+func (v *RequestObjectValidator) Parse(raw []byte, verifier Verifier, clientID string) (url.Values, error) {
+	token, err := Parse(raw, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	types := []string{TypeAuthzRequest}
+	if !v.RequireType {
+		types = append(types, "")
+	}
+	if err := checkType(token.Header(), typesOr(v.Types, types...)); err != nil {
+		return nil, err
+	}
+
+	var claims RequestObjectClaims
+	if err := token.DecodeClaims(&claims); err != nil {
+		return nil, err
+	}
+	var errs claimErrors
+	errs.require(claims.ClientID != "", "client_id")
+	errs.check(claims.ClientID == "" || constTimeEqual(claims.ClientID, clientID), "client_id", ErrClientMismatch)
+	errs.check(claims.Issuer == "" || claims.IsIssuer(clientID), "iss", ErrIssuerMismatch)
+	errs.check(claims.IsForAudience(v.Issuer), "aud", ErrAudienceMismatch)
+	errs.checkTime(&claims.RegisteredClaims, nowFunc(v.Now), v.Leeway)
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(token.Claims(), &all); err != nil {
+		return nil, err
+	}
+	return requestParams(all)
+}
+
+func requestParams(claims map[string]json.RawMessage) (url.Values, error) {
+	// See: https://datatracker.ietf.org/doc/html/rfc9101#section-4
+	var errs claimErrors
+	for _, name := range []string{"request", "request_uri"} {
+		_, ok := claims[name]
+		errs.check(!ok, name, ErrUnexpectedClaim)
+	}
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	params := make(url.Values, len(claims))
+	for name, raw := range claims {
+		switch name {
+		case "iss", "aud", "exp", "nbf", "iat", "jti":
+			continue
+		}
+
+		var value any
+		if err := decodeJSON(raw, &value); err != nil {
+			return nil, err
+		}
+		switch value := value.(type) {
+		case string:
+			params.Set(name, value)
+		case json.Number:
+			params.Set(name, value.String())
+		case bool:
+			params.Set(name, strconv.FormatBool(value))
+		case nil:
+			continue
+		default:
+			params.Set(name, string(raw))
+		}
+	}
+	return params, nil
+}
@@ -0,0 +1,147 @@
+package jwt
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestRequestObject(t *testing.T) {
+	builder := NewRequestObjectBuilder(must(NewSignerES(ES256, ecdsaPrivateKey256)))
+	verifier := must(NewVerifierES(ES256, ecdsaPublicKey256))
+
+	maxAge := int64(300)
+	token, err := builder.Build(&RequestObjectClaims{
+		RegisteredClaims: RegisteredClaims{
+			Audience:  Audience{"https://as.example.com"},
+			ExpiresAt: NewNumericDate(time.Now().Add(time.Minute)),
+		},
+		ClientID:     "client",
+		ResponseType: "code",
+		RedirectURI:  "https://client.example.com/cb",
+		Scope:        "openid profile",
+		State:        "state",
+		MaxAge:       &maxAge,
+	})
+	mustOk(t, err)
+	mustEqual(t, token.Header().Type, "oauth-authz-req+jwt")
+
+	validator := &RequestObjectValidator{Issuer: "https://as.example.com", RequireType: true}
+	params, err := validator.Parse(token.Bytes(), verifier, "client")
+	mustOk(t, err)
+	mustEqual(t, params, url.Values{
+		"client_id":     {"client"},
+		"response_type": {"code"},
+		"redirect_uri":  {"https://client.example.com/cb"},
+		"scope":         {"openid profile"},
+		"state":         {"state"},
+		"max_age":       {"300"},
+	})
+
+	_, err = validator.Parse(token.Bytes(), verifier, "another-client")
+	mustEqual(t, errors.Is(err, ErrClientMismatch), true)
+
+	var validationErr *ValidationError
+	mustEqual(t, errors.As(err, &validationErr), true)
+	mustEqual(t, validationErr.Error(), "token claims are not valid: client_id: token client is not expected; iss: token issuer is not expected")
+}
+
+func TestRequestObjectBuildBad(t *testing.T) {
+	builder := NewRequestObjectBuilder(must(NewSignerHS(HS256, hsKey256)))
+
+	testCases := []struct {
+		claims  *RequestObjectClaims
+		wantErr error
+	}{
+		{&RequestObjectClaims{RegisteredClaims: RegisteredClaims{Audience: Audience{"as"}}}, ErrMissingClaim},
+		{&RequestObjectClaims{ClientID: "client"}, ErrMissingClaim},
+		{
+			&RequestObjectClaims{RegisteredClaims: RegisteredClaims{Issuer: "other", Audience: Audience{"as"}}, ClientID: "client"},
+			ErrIssuerMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := builder.Build(tc.claims)
+		mustEqual(t, errors.Is(err, tc.wantErr), true)
+	}
+}
+
+func TestRequestObjectValidator(t *testing.T) {
+	signer := must(NewSignerHS(HS256, hsKey256))
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+	validator := &RequestObjectValidator{Issuer: "as"}
+
+	testCases := []struct {
+		opts    []BuilderOption
+		claims  any
+		want    url.Values
+		wantErr error
+	}{
+		{
+			nil,
+			map[string]any{"client_id": "client", "aud": "as"},
+			nil,
+			ErrTypeMismatch,
+		},
+		{
+			[]BuilderOption{WithType("")},
+			map[string]any{
+				"client_id": "client", "iss": "client", "aud": "as", "jti": "id",
+				"claims":    map[string]any{"id_token": map[string]any{"acr": nil}},
+				"essential": true,
+			},
+			url.Values{
+				"client_id": {"client"},
+				"claims":    {`{"id_token":{"acr":null}}`},
+				"essential": {"true"},
+			},
+			nil,
+		},
+		{
+			[]BuilderOption{WithType(TypeAuthzRequest)},
+			map[string]any{"aud": "as"},
+			nil,
+			ErrMissingClaim,
+		},
+		{
+			[]BuilderOption{WithType(TypeAuthzRequest)},
+			map[string]any{"client_id": "client", "iss": "other", "aud": "as"},
+			nil,
+			ErrIssuerMismatch,
+		},
+		{
+			[]BuilderOption{WithType(TypeAuthzRequest)},
+			map[string]any{"client_id": "client", "aud": "other-as"},
+			nil,
+			ErrAudienceMismatch,
+		},
+		{
+			[]BuilderOption{WithType(TypeAuthzRequest)},
+			map[string]any{"client_id": "client", "aud": "as", "exp": time.Now().Add(-time.Hour).Unix()},
+			nil,
+			ErrTokenExpired,
+		},
+		{
+			[]BuilderOption{WithType(TypeAuthzRequest)},
+			map[string]any{"client_id": "client", "aud": "as", "request": "nested"},
+			nil,
+			ErrUnexpectedClaim,
+		},
+		{
+			[]BuilderOption{WithType(TypeAuthzRequest)},
+			map[string]any{"client_id": "client", "aud": "as", "request_uri": nil},
+			nil,
+			ErrUnexpectedClaim,
+		},
+	}
+
+	for _, tc := range testCases {
+		token := must(NewBuilder(signer, tc.opts...).Build(tc.claims))
+
+		params, err := validator.Parse(token.Bytes(), verifier, "client")
+		mustEqual(t, errors.Is(err, tc.wantErr), true)
+		mustEqual(t, params, tc.want)
+	}
+}
@@ -0,0 +1,126 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RevocationStore keeps identifiers (`jti` claim) of revoked tokens.
+type RevocationStore interface {
+	// IsRevoked reports whether a token with a given id is revoked.
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+
+	// Revoke marks a token with a given id as revoked until a given time.
+	// Zero time means that token is revoked forever.
+	Revoke(ctx context.Context, jti string, until time.Time) error
+}
+
+// RevokeToken revokes a token by it's `jti` claim until token expiration.
+func RevokeToken(ctx context.Context, store RevocationStore, token *Token) error {
+	var claims RegisteredClaims
+	if err := token.DecodeRegisteredClaims(&claims); err != nil {
+		return err
+	}
+	if claims.ID == "" {
+		return ErrMissingID
+	}
+
+	var until time.Time
+	if claims.ExpiresAt != nil {
+		until = claims.ExpiresAt.Time
+	}
+	return store.Revoke(ctx, claims.ID, until)
+}
+
+// NewRevocationValidator returns a Validator that rejects revoked tokens.
+// Tokens without `jti` claim are rejected with ErrMissingID.
+func NewRevocationValidator(store RevocationStore) Validator {
+	return ValidatorFunc(func(ctx context.Context, token *Token) error {
+		var claims RegisteredClaims
+		if err := token.DecodeRegisteredClaims(&claims); err != nil {
+			return err
+		}
+		if claims.ID == "" {
+			return ErrMissingID
+		}
+
+		revoked, err := store.IsRevoked(ctx, claims.ID)
+		switch {
+		case err != nil:
+			return err
+		case revoked:
+			return ErrTokenRevoked
+		default:
+			return nil
+		}
+	})
+}
+
+// MemoryRevocationStore is an in-memory RevocationStore.
+// Entries are removed after their expiration time.
+// Safe to use concurrently.
+type MemoryRevocationStore struct {
+	mu        sync.RWMutex
+	entries   map[string]time.Time
+	nextSweep int
+	now       func() time.Time
+}
+
+const minRevocationSweep = 64
+
+// NewMemoryRevocationStore returns new instance of MemoryRevocationStore.
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{
+		entries:   map[string]time.Time{},
+		nextSweep: minRevocationSweep,
+		now:       time.Now,
+	}
+}
+
+// IsRevoked implements RevocationStore interface.
+func (s *MemoryRevocationStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	s.mu.RLock()
+	until, ok := s.entries[jti]
+	s.mu.RUnlock()
+
+	return ok && (until.IsZero() || until.After(s.now())), nil
+}
+
+// Revoke implements RevocationStore interface.
+func (s *MemoryRevocationStore) Revoke(ctx context.Context, jti string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if old, ok := s.entries[jti]; ok && (old.IsZero() || (!until.IsZero() && old.After(until))) {
+		return nil
+	}
+	s.entries[jti] = until
+
+	if len(s.entries) >= s.nextSweep {
+		s.sweep()
+	}
+	return nil
+}
+
+// Len returns number of stored entries.
+func (s *MemoryRevocationStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.entries)
+}
+
+// sweep removes expired entries, must be called under a lock.
+func (s *MemoryRevocationStore) sweep() {
+	now := s.now()
+	for jti, until := range s.entries {
+		if !until.IsZero() && !until.After(now) {
+			delete(s.entries, jti)
+		}
+	}
+
+	s.nextSweep = 2 * len(s.entries)
+	if s.nextSweep < minRevocationSweep {
+		s.nextSweep = minRevocationSweep
+	}
+}
@@ -0,0 +1,85 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRevocation(t *testing.T) {
+	ctx := context.Background()
+	signer := must(NewSignerHS(HS256, hsKey256))
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+	builder := NewBuilder(signer)
+
+	store := NewMemoryRevocationStore()
+	validator := NewRevocationValidator(store)
+
+	token := must(builder.Build(&RegisteredClaims{
+		ID:        "token-id",
+		ExpiresAt: NewNumericDate(time.Now().Add(time.Hour)),
+	}))
+
+	_, err := ParseAndValidate(ctx, token.Bytes(), verifier, validator)
+	mustOk(t, err)
+
+	mustOk(t, RevokeToken(ctx, store, token))
+
+	_, err = ParseAndValidate(ctx, token.Bytes(), verifier, validator)
+	mustEqual(t, err, ErrTokenRevoked)
+
+	noID := must(builder.Build(&RegisteredClaims{Subject: "subject"}))
+	_, err = ParseAndValidate(ctx, noID.Bytes(), verifier, validator)
+	mustEqual(t, err, ErrMissingID)
+	mustEqual(t, RevokeToken(ctx, store, noID), ErrMissingID)
+}
+
+func TestMemoryRevocationStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	store := NewMemoryRevocationStore()
+	store.now = func() time.Time { return now }
+
+	mustOk(t, store.Revoke(ctx, "forever", time.Time{}))
+	mustOk(t, store.Revoke(ctx, "hour", now.Add(time.Hour)))
+	mustOk(t, store.Revoke(ctx, "expired", now.Add(-time.Second)))
+
+	testCases := []struct {
+		jti  string
+		want bool
+	}{
+		{"forever", true},
+		{"hour", true},
+		{"expired", false},
+		{"unknown", false},
+	}
+
+	for _, tc := range testCases {
+		revoked, err := store.IsRevoked(ctx, tc.jti)
+		mustOk(t, err)
+		mustEqual(t, revoked, tc.want)
+	}
+
+	// shorter revocation must not shorten an existing one.
+	mustOk(t, store.Revoke(ctx, "forever", now.Add(-time.Hour)))
+	mustOk(t, store.Revoke(ctx, "hour", now.Add(-time.Hour)))
+	mustEqual(t, must(store.IsRevoked(ctx, "forever")), true)
+	mustEqual(t, must(store.IsRevoked(ctx, "hour")), true)
+}
+
+func TestMemoryRevocationStoreSweep(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	store := NewMemoryRevocationStore()
+	store.now = func() time.Time { return now }
+
+	for i := 0; i < minRevocationSweep-1; i++ {
+		mustOk(t, store.Revoke(ctx, string(rune('a'+i)), now.Add(-time.Second)))
+	}
+	mustEqual(t, store.Len(), minRevocationSweep-1)
+
+	mustOk(t, store.Revoke(ctx, "alive", now.Add(time.Hour)))
+	mustEqual(t, store.Len(), 1)
+}
@@ -0,0 +1,505 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sdAlg is the only supported `_sd_alg` value.
+const sdAlg = "sha-256"
+
+// Disclosure is a selectively disclosable claim of SD-JWT.
+// See: https://datatracker.ietf.org/doc/html/draft-ietf-oauth-selective-disclosure-jwt#section-4.2
+type Disclosure struct {
+	Salt string
+
+	// Name of the claim, empty for an array element disclosure.
+	Name string
+
+	Value any
+
+	isElem  bool
+	encoded string
+}
+
+// NewDisclosure returns a disclosure of an object property with a random salt.
+func NewDisclosure(name string, value any) (*Disclosure, error) {
+	return newDisclosure(name, value, false)
+}
+
+// NewElementDisclosure returns a disclosure of an array element with a random salt.
+// Use it's digest as `{"...": digest}` array element.
+func NewElementDisclosure(value any) (*Disclosure, error) {
+	return newDisclosure("", value, true)
+}
+
+func newDisclosure(name string, value any, isElem bool) (*Disclosure, error) {
+	salt, err := newTokenID()
+	if err != nil {
+		return nil, err
+	}
+
+	arr := []any{salt, name, value}
+	if isElem {
+		arr = []any{salt, value}
+	}
+	raw, err := json.Marshal(arr)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Disclosure{
+		Salt:    salt,
+		Name:    name,
+		Value:   value,
+		isElem:  isElem,
+		encoded: b64EncodeString(raw),
+	}
+	return d, nil
+}
+
+// ParseDisclosure decodes a base64url encoded disclosure.
+func ParseDisclosure(s string) (*Disclosure, error) {
+	raw, err := b64DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidDisclosure
+	}
+	var arr []any
+	if err := decodeJSON(raw, &arr); err != nil {
+		return nil, ErrInvalidDisclosure
+	}
+
+	if len(arr) != 2 && len(arr) != 3 {
+		return nil, ErrInvalidDisclosure
+	}
+
+	salt, ok := arr[0].(string)
+	if !ok {
+		return nil, ErrInvalidDisclosure
+	}
+	d := &Disclosure{
+		Salt:    salt,
+		Value:   arr[len(arr)-1],
+		isElem:  len(arr) == 2,
+		encoded: s,
+	}
+	if !d.isElem {
+		d.Name, ok = arr[1].(string)
+		if !ok || d.Name == "_sd" || d.Name == "..." {
+			return nil, ErrInvalidDisclosure
+		}
+	}
+	return d, nil
+}
+
+// String returns base64url encoded disclosure.
+func (d *Disclosure) String() string {
+	return d.encoded
+}
+
+// Digest returns base64url encoded SHA-256 digest of the disclosure.
+func (d *Disclosure) Digest() string {
+	return sdDigest(d.encoded)
+}
+
+// SDJWT represents SD-JWT in a combined format:
+// `<Issuer-signed JWT>~<Disclosure 1>~...~<Disclosure N>~<optional KB-JWT>`.
+// See: https://datatracker.ietf.org/doc/html/draft-ietf-oauth-selective-disclosure-jwt#section-4
+type SDJWT struct {
+	Token       *Token
+	Disclosures []*Disclosure
+	KeyBinding  *Token
+}
+
+// ParseSDJWT splits SD-JWT in a combined format.
+// Signatures and disclosures are not verified, see SDJWTValidator.
+func ParseSDJWT(raw []byte) (*SDJWT, error) {
+	parts := strings.Split(string(raw), "~")
+	if len(parts) < 2 {
+		return nil, ErrInvalidFormat
+	}
+
+	token, err := ParseNoVerify([]byte(parts[0]))
+	if err != nil {
+		return nil, err
+	}
+	sd := &SDJWT{Token: token}
+
+	for _, p := range parts[1 : len(parts)-1] {
+		d, err := ParseDisclosure(p)
+		if err != nil {
+			return nil, err
+		}
+		sd.Disclosures = append(sd.Disclosures, d)
+	}
+
+	if kb := parts[len(parts)-1]; kb != "" {
+		if sd.KeyBinding, err = ParseNoVerify([]byte(kb)); err != nil {
+			return nil, err
+		}
+	}
+	return sd, nil
+}
+
+// String returns SD-JWT in a combined format.
+func (sd *SDJWT) String() string {
+	s := sd.presentation()
+	if sd.KeyBinding != nil {
+		s += sd.KeyBinding.String()
+	}
+	return s
+}
+
+// presentation returns combined format without a key binding JWT, used for `sd_hash`.
+func (sd *SDJWT) presentation() string {
+	var b strings.Builder
+	b.WriteString(sd.Token.String())
+	b.WriteByte('~')
+	for _, d := range sd.Disclosures {
+		b.WriteString(d.String())
+		b.WriteByte('~')
+	}
+	return b.String()
+}
+
+// Present returns SD-JWT with disclosures of given object property names only, used by holders.
+// Key binding JWT is dropped.
+func (sd *SDJWT) Present(names ...string) *SDJWT {
+	return sd.PresentFunc(func(d *Disclosure) bool {
+		return !d.isElem && containsString(names, d.Name)
+	})
+}
+
+// PresentFunc returns SD-JWT with disclosures for which keep reports true, used by holders.
+// Key binding JWT is dropped.
+func (sd *SDJWT) PresentFunc(keep func(d *Disclosure) bool) *SDJWT {
+	res := &SDJWT{Token: sd.Token}
+	for _, d := range sd.Disclosures {
+		if keep(d) {
+			res.Disclosures = append(res.Disclosures, d)
+		}
+	}
+	return res
+}
+
+// KeyBindingClaims represents claims of SD-JWT key binding JWT.
+type KeyBindingClaims struct {
+	IssuedAt *NumericDate `json:"iat"`
+	Audience Audience     `json:"aud"`
+	Nonce    string       `json:"nonce"`
+	SDHash   string       `json:"sd_hash"`
+}
+
+// Bind returns SD-JWT with a key binding JWT signed by the holder key.
+func (sd *SDJWT) Bind(signer Signer, audience, nonce string) (*SDJWT, error) {
+	res := &SDJWT{Token: sd.Token, Disclosures: sd.Disclosures}
+
+	kb, err := NewBuilder(signer, WithType(TypeKeyBinding)).Build(&KeyBindingClaims{
+		IssuedAt: NewNumericDate(time.Now()),
+		Audience: Audience{audience},
+		Nonce:    nonce,
+		SDHash:   sdDigest(res.presentation()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.KeyBinding = kb
+	return res, nil
+}
+
+// SDJWTIssuer creates SD-JWTs.
+// Safe to use concurrently.
+type SDJWTIssuer struct {
+	builder *Builder
+}
+
+// NewSDJWTIssuer returns new instance of SDJWTIssuer.
+func NewSDJWTIssuer(signer Signer, opts ...BuilderOption) *SDJWTIssuer {
+	return &SDJWTIssuer{
+		builder: NewBuilder(signer, opts...),
+	}
+}
+
+// Issue builds SD-JWT where top-level claims with given names are selectively disclosable.
+// To bind SD-JWT to a holder key put Confirmation with JWK into `cnf` claim.
+func (i *SDJWTIssuer) Issue(claims map[string]any, disclosable ...string) (*SDJWT, error) {
+	payload := make(map[string]any, len(claims)+2)
+	var disclosures []*Disclosure
+	var digests []string
+
+	for name, value := range claims {
+		if !containsString(disclosable, name) {
+			payload[name] = value
+			continue
+		}
+		d, err := NewDisclosure(name, value)
+		if err != nil {
+			return nil, err
+		}
+		disclosures = append(disclosures, d)
+		digests = append(digests, d.Digest())
+	}
+	if len(digests) > 0 {
+		// digests must not reveal claims order.
+		sort.Strings(digests)
+		payload["_sd"] = digests
+		payload["_sd_alg"] = sdAlg
+	}
+
+	token, err := i.builder.Build(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &SDJWT{Token: token, Disclosures: disclosures}, nil
+}
+
+// SDJWTValidator verifies SD-JWTs and rebuilds disclosed claims, used by verifiers.
+// See: https://datatracker.ietf.org/doc/html/draft-ietf-oauth-selective-disclosure-jwt#section-7
+type SDJWTValidator struct {
+	// Audience is an expected `aud` claim of key binding JWT.
+	Audience string
+
+	// RequireKeyBinding rejects SD-JWTs without key binding JWT.
+	RequireKeyBinding bool
+
+	// MaxAge is a max allowed age of key binding JWT, default is 5 minutes.
+	MaxAge time.Duration
+
+	// Leeway is an allowed clock skew for time claims.
+	Leeway time.Duration
+
+	// Now returns current time, time.Now is used if nil.
+	Now func() time.Time
+}
+
+// Parse verifies SD-JWT and returns claims with disclosures applied.
+// Time claims of the issuer-signed JWT are checked before disclosures are applied.
+// Nonce is an expected `nonce` claim of key binding JWT.
+func (v *SDJWTValidator) Parse(raw []byte, verifier Verifier, nonce string) (map[string]any, error) {
+	sd, err := ParseSDJWT(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifier.Verify(sd.Token); err != nil {
+		return nil, err
+	}
+
+	var registered RegisteredClaims
+	if err := sd.Token.DecodeRegisteredClaims(&registered); err != nil {
+		return nil, err
+	}
+	var errs claimErrors
+	errs.checkTime(&registered, nowFunc(v.Now), v.Leeway)
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]any
+	if err := decodeJSON(sd.Token.Claims(), &claims); err != nil {
+		return nil, err
+	}
+	if alg, ok := claims["_sd_alg"]; ok && alg != sdAlg {
+		return nil, ErrUnsupportedAlg
+	}
+	delete(claims, "_sd_alg")
+
+	if err := applyDisclosures(claims, sd.Disclosures); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case sd.KeyBinding != nil:
+		if err := v.validateKeyBinding(sd, claims, nonce); err != nil {
+			return nil, err
+		}
+	case v.RequireKeyBinding:
+		return nil, ErrKeyNotFound
+	}
+	return claims, nil
+}
+
+func (v *SDJWTValidator) validateKeyBinding(sd *SDJWT, claims map[string]any, nonce string) error {
+	if !hasType(sd.KeyBinding.Header(), TypeKeyBinding) {
+		return ErrTypeMismatch
+	}
+
+	var cnf struct {
+		Confirmation Confirmation `json:"cnf"`
+	}
+	raw, err := json.Marshal(claims)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, &cnf); err != nil || cnf.Confirmation.JWK == nil {
+		return ErrKeyNotFound
+	}
+	verifier, err := cnf.Confirmation.JWK.Verifier(sd.KeyBinding.Header().Algorithm)
+	if err != nil {
+		return err
+	}
+	if err := verifier.Verify(sd.KeyBinding); err != nil {
+		return err
+	}
+
+	var kb KeyBindingClaims
+	if err := sd.KeyBinding.DecodeClaims(&kb); err != nil {
+		return err
+	}
+	maxAge := v.MaxAge
+	if maxAge == 0 {
+		maxAge = 5 * time.Minute
+	}
+	now := nowFunc(v.Now)
+
+	var errs claimErrors
+	errs.require(kb.IssuedAt != nil, "iat")
+	errs.check(constTimeEqual(kb.SDHash, sdDigest(sd.presentation())), "sd_hash", ErrHashMismatch)
+	errs.check(constTimeEqual(kb.Nonce, nonce), "nonce", ErrNonceMismatch)
+	errs.check((&RegisteredClaims{Audience: kb.Audience}).IsForAudience(v.Audience), "aud", ErrAudienceMismatch)
+	errs.check(kb.IssuedAt == nil || !kb.IssuedAt.After(now.Add(v.Leeway)), "iat", ErrTokenNotValidYet)
+	errs.checkAge(kb.IssuedAt, now, maxAge, v.Leeway)
+	return errs.err()
+}
+
+// applyDisclosures replaces digests in claims with disclosed values.
+// Every disclosure must be referenced exactly once.
+func applyDisclosures(claims map[string]any, disclosures []*Disclosure) error {
+	p := &sdProcessor{
+		byDigest: make(map[string]*Disclosure, len(disclosures)),
+		used:     make(map[string]bool, len(disclosures)),
+	}
+	for _, d := range disclosures {
+		p.byDigest[d.Digest()] = d
+	}
+
+	if err := p.object(claims); err != nil {
+		return err
+	}
+	if len(p.used) != len(p.byDigest) || len(p.byDigest) != len(disclosures) {
+		return ErrInvalidDisclosure
+	}
+	return nil
+}
+
+type sdProcessor struct {
+	byDigest map[string]*Disclosure
+	used     map[string]bool
+}
+
+func (p *sdProcessor) object(obj map[string]any) error {
+	if raw, ok := obj["_sd"]; ok {
+		delete(obj, "_sd")
+		digests, ok := raw.([]any)
+		if !ok {
+			return ErrInvalidDisclosure
+		}
+
+		for _, digest := range digests {
+			d, err := p.lookup(digest)
+			switch {
+			case err != nil:
+				return err
+			case d == nil:
+				continue // decoy digest
+			case d.isElem:
+				return ErrInvalidDisclosure
+			}
+			if _, ok := obj[d.Name]; ok {
+				return ErrInvalidDisclosure
+			}
+			obj[d.Name] = d.Value
+		}
+	}
+
+	for name, value := range obj {
+		v, err := p.value(value)
+		if err != nil {
+			return err
+		}
+		obj[name] = v
+	}
+	return nil
+}
+
+func (p *sdProcessor) array(arr []any) ([]any, error) {
+	res := make([]any, 0, len(arr))
+	for _, elem := range arr {
+		if obj, ok := elem.(map[string]any); ok {
+			if digest, ok := obj["..."]; ok {
+				if len(obj) != 1 {
+					return nil, ErrInvalidDisclosure
+				}
+				d, err := p.lookup(digest)
+				switch {
+				case err != nil:
+					return nil, err
+				case d == nil:
+					continue // decoy digest
+				case !d.isElem:
+					return nil, ErrInvalidDisclosure
+				}
+				elem = d.Value
+			}
+		}
+
+		v, err := p.value(elem)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, v)
+	}
+	return res, nil
+}
+
+func (p *sdProcessor) value(v any) (any, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		return v, p.object(v)
+	case []any:
+		return p.array(v)
+	default:
+		return v, nil
+	}
+}
+
+// lookup returns a disclosure by digest or nil if there is no such disclosure.
+func (p *sdProcessor) lookup(digest any) (*Disclosure, error) {
+	s, ok := digest.(string)
+	if !ok {
+		return nil, ErrInvalidDisclosure
+	}
+	d, ok := p.byDigest[s]
+	if !ok {
+		return nil, nil
+	}
+	if p.used[s] {
+		return nil, ErrInvalidDisclosure
+	}
+	p.used[s] = true
+	return d, nil
+}
+
+func sdDigest(s string) string {
+	digest := sha256.Sum256([]byte(s))
+	return b64EncodeString(digest[:])
+}
+
+// decodeJSON decodes JSON keeping numbers as json.Number.
+func decodeJSON(raw []byte, dst any) error {
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	return d.Decode(dst)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
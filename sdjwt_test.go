@@ -0,0 +1,163 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDisclosure(t *testing.T) {
+	// See: https://datatracker.ietf.org/doc/html/draft-ietf-oauth-selective-disclosure-jwt#section-4.2.1
+	d, err := ParseDisclosure("WyJfMjZiYzRMVC1hYzZxMktJNmNCVzVlcyIsICJmYW1pbHlfbmFtZSIsICJNw7ZiaXVzIl0")
+	mustOk(t, err)
+	mustEqual(t, d.Salt, "_26bc4LT-ac6q2KI6cBW5es")
+	mustEqual(t, d.Name, "family_name")
+	mustEqual(t, d.Value, any("Möbius"))
+	mustEqual(t, d.Digest(), "X9yH0Ajrdm1Oij4tWso9UzzKJvPoDxwmuEcO3XAdRC0")
+
+	testCases := []string{
+		"!",
+		bytesToBase64([]byte(`{}`)),
+		bytesToBase64([]byte(`["salt"]`)),
+		bytesToBase64([]byte(`[1, "name", "value"]`)),
+		bytesToBase64([]byte(`["salt", 1, "value"]`)),
+		bytesToBase64([]byte(`["salt", "_sd", "value"]`)),
+		bytesToBase64([]byte(`["salt", "...", "value"]`)),
+	}
+	for _, tc := range testCases {
+		_, err := ParseDisclosure(tc)
+		mustEqual(t, err, ErrInvalidDisclosure)
+	}
+}
+
+func TestSDJWT(t *testing.T) {
+	issuer := NewSDJWTIssuer(must(NewSignerES(ES256, ecdsaPrivateKey256)), WithType("example+sd-jwt"))
+	verifier := must(NewVerifierES(ES256, ecdsaPublicKey256))
+	holder := must(NewSignerEdDSA(ed25519PrivateKey))
+
+	claims := map[string]any{
+		"iss":         "https://issuer.example.com",
+		"sub":         "user",
+		"given_name":  "John",
+		"family_name": "Doe",
+		"address":     map[string]any{"country": "DE"},
+		"cnf":         Confirmation{JWK: must(NewJWK(ed25519PublicKey))},
+	}
+	sd, err := issuer.Issue(claims, "given_name", "family_name", "address")
+	mustOk(t, err)
+	mustEqual(t, len(sd.Disclosures), 3)
+
+	var issued map[string]any
+	mustOk(t, sd.Token.DecodeClaims(&issued))
+	mustEqual(t, issued["given_name"], nil)
+	mustEqual(t, issued["_sd_alg"], any("sha-256"))
+	mustEqual(t, len(issued["_sd"].([]any)), 3)
+
+	parsed, err := ParseSDJWT([]byte(sd.String()))
+	mustOk(t, err)
+	mustEqual(t, parsed.String(), sd.String())
+
+	presented, err := sd.Present("given_name", "address").Bind(holder, "https://verifier.example.com", "nonce")
+	mustOk(t, err)
+
+	validator := &SDJWTValidator{
+		Audience:          "https://verifier.example.com",
+		RequireKeyBinding: true,
+	}
+	disclosed, err := validator.Parse([]byte(presented.String()), verifier, "nonce")
+	mustOk(t, err)
+	mustEqual(t, disclosed["sub"], any("user"))
+	mustEqual(t, disclosed["given_name"], any("John"))
+	mustEqual(t, disclosed["address"], any(map[string]any{"country": "DE"}))
+	mustEqual(t, disclosed["family_name"], nil)
+	mustEqual(t, disclosed["_sd"], nil)
+	mustEqual(t, disclosed["_sd_alg"], nil)
+
+	_, err = validator.Parse([]byte(presented.String()), verifier, "another-nonce")
+	mustEqual(t, errors.Is(err, ErrNonceMismatch), true)
+
+	// key binding is required.
+	_, err = validator.Parse([]byte(sd.Present("given_name").String()), verifier, "nonce")
+	mustEqual(t, err, ErrKeyNotFound)
+
+	// disclosures were changed after key binding.
+	tampered := *presented
+	tampered.Disclosures = presented.Disclosures[:1]
+	_, err = validator.Parse([]byte(tampered.String()), verifier, "nonce")
+	mustEqual(t, errors.Is(err, ErrHashMismatch), true)
+
+	// key binding is signed by another key.
+	another, err := sd.Present().Bind(must(NewSignerEdDSA(ed25519PrivateKeyAnother)), "https://verifier.example.com", "nonce")
+	mustOk(t, err)
+	_, err = validator.Parse([]byte(another.String()), verifier, "nonce")
+	mustEqual(t, err, ErrInvalidSignature)
+
+	// key binding is too old.
+	validator.Now = func() time.Time { return time.Now().Add(time.Hour) }
+	_, err = validator.Parse([]byte(presented.String()), verifier, "nonce")
+	mustEqual(t, errors.Is(err, ErrTokenExpired), true)
+}
+
+func TestSDJWTRecursive(t *testing.T) {
+	signer := must(NewSignerHS(HS256, hsKey256))
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+
+	country := must(NewDisclosure("country", "DE"))
+	address := must(NewDisclosure("address", map[string]any{"_sd": []string{country.Digest()}}))
+	nationality := must(NewElementDisclosure("FR"))
+
+	token := must(NewBuilder(signer).Build(map[string]any{
+		"_sd":           []string{address.Digest(), "decoy-digest"},
+		"nationalities": []any{"US", map[string]string{"...": nationality.Digest()}, map[string]string{"...": "decoy"}},
+		"age":           42,
+	}))
+
+	sd := &SDJWT{Token: token, Disclosures: []*Disclosure{address, country, nationality}}
+
+	claims, err := (&SDJWTValidator{}).Parse([]byte(sd.String()), verifier, "")
+	mustOk(t, err)
+	mustEqual(t, claims, map[string]any{
+		"address":       map[string]any{"country": "DE"},
+		"nationalities": []any{"US", "FR"},
+		"age":           json.Number("42"),
+	})
+
+	testCases := [][]*Disclosure{
+		// country is not referenced without address.
+		{country},
+		// duplicated disclosure.
+		{address, country, country},
+	}
+	for _, tc := range testCases {
+		sd := &SDJWT{Token: token, Disclosures: tc}
+		_, err := (&SDJWTValidator{}).Parse([]byte(sd.String()), verifier, "")
+		mustEqual(t, err, ErrInvalidDisclosure)
+	}
+}
+
+func TestSDJWTValidatorTime(t *testing.T) {
+	issuer := NewSDJWTIssuer(must(NewSignerHS(HS256, hsKey256)))
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+	now := time.Now()
+
+	testCases := []struct {
+		claims  map[string]any
+		leeway  time.Duration
+		wantErr error
+	}{
+		{map[string]any{"exp": now.Add(time.Hour).Unix(), "nbf": now.Unix()}, 0, nil},
+		{map[string]any{"exp": now.Add(-24 * time.Hour).Unix()}, 0, ErrTokenExpired},
+		{map[string]any{"nbf": now.Add(time.Hour).Unix()}, 0, ErrTokenNotValidYet},
+		{map[string]any{"iat": now.Add(time.Hour).Unix()}, 0, ErrTokenNotValidYet},
+		{map[string]any{"exp": now.Add(-30 * time.Second).Unix()}, time.Minute, nil},
+	}
+
+	for _, tc := range testCases {
+		sd := must(issuer.Issue(tc.claims))
+
+		validator := &SDJWTValidator{Leeway: tc.leeway}
+		_, err := validator.Parse([]byte(sd.String()), verifier, "")
+		mustEqual(t, errors.Is(err, tc.wantErr), true)
+	}
+}
@@ -0,0 +1,265 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// SecurityEventClaims represents claims of Security Event Token.
+// See: https://datatracker.ietf.org/doc/html/rfc8417#section-2.2
+type SecurityEventClaims struct {
+	RegisteredClaims
+
+	// Events claim maps event type identifiers to event payloads.
+	Events map[string]json.RawMessage `json:"events"`
+
+	// TransactionID claim is an identifier of the transaction that produced the events.
+	TransactionID string `json:"txn,omitempty"`
+
+	// TimeOfEvent claim is the time when the event occurred.
+	TimeOfEvent *NumericDate `json:"toe,omitempty"`
+}
+
+// SETValidator validates Security Event Tokens.
+// See: https://datatracker.ietf.org/doc/html/rfc8417#section-4
+type SETValidator struct {
+	// Issuer is an expected `iss` claim, required.
+	Issuer string
+
+	// Audience is an expected `aud` value, not checked if empty.
+	Audience string
+
+	// AllowUntyped accepts tokens without `typ` header.
+	// By default only `secevent+jwt` type is accepted.
+	AllowUntyped bool
+
+	// Types are allowed `typ` header values, overrides default types and AllowUntyped if not empty.
+	Types []string
+
+	// Replay rejects an already received token, not checked if nil.
+	Replay *ReplayGuard
+
+	// ReplayWindow is a time to remember received tokens, default is 1 hour.
+	ReplayWindow time.Duration
+
+	// Leeway is an allowed clock skew for time claims.
+	Leeway time.Duration
+
+	// Now returns current time, time.Now is used if nil.
+	Now func() time.Time
+}
+
+// Parse decodes a SET, verifies it's signature and validates claims.
+// Returns ErrInvalidConfig if Issuer is empty.
+func (v *SETValidator) Parse(raw []byte, verifier Verifier) (*SecurityEventClaims, error) {
+	if v.Issuer == "" {
+		return nil, ErrInvalidConfig
+	}
+
+	token, err := Parse(raw, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	types := []string{TypeSecEvent}
+	if v.AllowUntyped {
+		types = append(types, "")
+	}
+	if err := checkType(token.Header(), typesOr(v.Types, types...)); err != nil {
+		return nil, err
+	}
+
+	var claims SecurityEventClaims
+	if err := token.DecodeClaims(&claims); err != nil {
+		return nil, err
+	}
+	var errs claimErrors
+	errs.require(claims.Issuer != "", "iss")
+	errs.check(claims.Issuer == "" || claims.IsIssuer(v.Issuer), "iss", ErrIssuerMismatch)
+	errs.check(v.Audience == "" || claims.IsForAudience(v.Audience), "aud", ErrAudienceMismatch)
+	errs.check(claims.ID != "", "jti", ErrMissingID)
+	errs.require(claims.IssuedAt != nil, "iat")
+	errs.require(len(claims.Events) != 0, "events")
+
+	now := nowFunc(v.Now)
+	errs.checkTime(&claims.RegisteredClaims, now, v.Leeway)
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	if v.Replay != nil {
+		window := v.ReplayWindow
+		if window == 0 {
+			window = time.Hour
+		}
+		if err := v.Replay.Use(replayKey(claims.Issuer, claims.ID), now.Add(window)); err != nil {
+			return nil, err
+		}
+	}
+	return &claims, nil
+}
+
+// SETDeliveryError is an error response of SET push delivery.
+// See: https://datatracker.ietf.org/doc/html/rfc8935#section-2.3
+type SETDeliveryError struct {
+	Code        string `json:"err"`
+	Description string `json:"description,omitempty"`
+}
+
+func (e *SETDeliveryError) Error() string {
+	if e.Description == "" {
+		return "SET delivery failed: " + e.Code
+	}
+	return "SET delivery failed: " + e.Code + ": " + e.Description
+}
+
+// NewSETReceiver returns an http.Handler for SET push delivery.
+// Handler validates a SET and calls handle func, errors are answered as described in RFC 8935.
+// If handle func returns *SETDeliveryError it's returned to the transmitter as is,
+// other handle func errors and server errors are answered with 500 without details.
+// See: https://datatracker.ietf.org/doc/html/rfc8935#section-2
+func NewSETReceiver(verifier Verifier, validator *SETValidator, handle func(ctx context.Context, claims *SecurityEventClaims) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if mediaType != "application/secevent+jwt" {
+			writeSETError(w, &SETDeliveryError{Code: "invalid_request", Description: "unexpected content type"})
+			return
+		}
+
+		raw, err := io.ReadAll(io.LimitReader(r.Body, maxResponseSize))
+		if err != nil {
+			writeSETError(w, &SETDeliveryError{Code: "invalid_request", Description: err.Error()})
+			return
+		}
+
+		claims, err := validator.Parse(bytes.TrimSpace(raw), verifier)
+		if err != nil {
+			if !isInvalidToken(err) {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			writeSETError(w, setDeliveryError(err))
+			return
+		}
+		if err := handle(r.Context(), claims); err != nil {
+			var deliveryErr *SETDeliveryError
+			if !errors.As(err, &deliveryErr) {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			writeSETError(w, deliveryErr)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+// setDeliveryError maps a token error to a SET delivery error.
+func setDeliveryError(err error) *SETDeliveryError {
+	code := "invalid_request"
+	switch {
+	case errors.Is(err, ErrInvalidSignature), errors.Is(err, ErrAlgorithmMismatch), errors.Is(err, ErrKeyNotFound):
+		code = "invalid_key"
+	case errors.Is(err, ErrIssuerMismatch):
+		code = "invalid_issuer"
+	case errors.Is(err, ErrAudienceMismatch):
+		code = "invalid_audience"
+	}
+	return &SETDeliveryError{Code: code, Description: err.Error()}
+}
+
+func writeSETError(w http.ResponseWriter, err *SETDeliveryError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(err)
+}
+
+// SETTransmitter signs and pushes SETs to receivers.
+// Safe to use concurrently.
+type SETTransmitter struct {
+	builder *Builder
+	client  *http.Client
+}
+
+// NewSETTransmitter returns new instance of SETTransmitter.
+// Header `typ` is always set to `secevent+jwt`.
+// If client is nil http.DefaultClient is used.
+func NewSETTransmitter(signer Signer, client *http.Client, opts ...BuilderOption) *SETTransmitter {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	opts = append(opts[:len(opts):len(opts)], WithType(TypeSecEvent))
+	return &SETTransmitter{
+		builder: NewBuilder(signer, opts...),
+		client:  client,
+	}
+}
+
+// Build builds a SET, `jti` and `iat` claims are set if empty.
+func (t *SETTransmitter) Build(claims *SecurityEventClaims) (*Token, error) {
+	if len(claims.Events) == 0 {
+		return nil, missingClaim("events")
+	}
+
+	c := *claims
+	if c.ID == "" {
+		id, err := newTokenID()
+		if err != nil {
+			return nil, err
+		}
+		c.ID = id
+	}
+	if c.IssuedAt == nil {
+		c.IssuedAt = NewNumericDate(time.Now())
+	}
+	return t.builder.Build(&c)
+}
+
+// Push builds a SET and delivers it to a receiver endpoint.
+// Receiver error response is returned as *SETDeliveryError.
+// See: https://datatracker.ietf.org/doc/html/rfc8935#section-2
+func (t *SETTransmitter) Push(ctx context.Context, endpoint string, claims *SecurityEventClaims) error {
+	token, err := t.Build(claims)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(token.Bytes()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/secevent+jwt")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusAccepted, http.StatusOK:
+		return nil
+	case http.StatusBadRequest:
+		var deliveryErr SETDeliveryError
+		body := io.LimitReader(resp.Body, maxResponseSize)
+		if err := json.NewDecoder(body).Decode(&deliveryErr); err != nil || deliveryErr.Code == "" {
+			return &HTTPStatusError{URL: endpoint, StatusCode: resp.StatusCode}
+		}
+		return &deliveryErr
+	default:
+		return &HTTPStatusError{URL: endpoint, StatusCode: resp.StatusCode}
+	}
+}
@@ -0,0 +1,174 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testEvent = "https://schemas.openid.net/secevent/caep/event-type/session-revoked"
+
+func TestSETPush(t *testing.T) {
+	signer := must(NewSignerES(ES256, ecdsaPrivateKey256))
+	verifier := must(NewVerifierES(ES256, ecdsaPublicKey256))
+
+	validator := &SETValidator{
+		Issuer:   "https://tx.example.com",
+		Audience: "https://rx.example.com",
+		Replay:   NewReplayGuard(100, 0),
+	}
+
+	var received []*SecurityEventClaims
+	receiver := NewSETReceiver(verifier, validator, func(ctx context.Context, claims *SecurityEventClaims) error {
+		switch claims.Subject {
+		case "denied":
+			return &SETDeliveryError{Code: "access_denied"}
+		case "failed":
+			return errors.New("database is down")
+		}
+		received = append(received, claims)
+		return nil
+	})
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	transmitter := NewSETTransmitter(signer, srv.Client())
+	ctx := context.Background()
+
+	claims := &SecurityEventClaims{
+		RegisteredClaims: RegisteredClaims{
+			Issuer:   "https://tx.example.com",
+			Audience: Audience{"https://rx.example.com"},
+			Subject:  "user",
+		},
+		Events:        map[string]json.RawMessage{testEvent: json.RawMessage(`{"reason":"logout"}`)},
+		TransactionID: "txn",
+		TimeOfEvent:   NewNumericDate(time.Now()),
+	}
+
+	mustOk(t, transmitter.Push(ctx, srv.URL, claims))
+	mustEqual(t, len(received), 1)
+	mustEqual(t, received[0].TransactionID, "txn")
+	mustEqual(t, string(received[0].Events[testEvent]), `{"reason":"logout"}`)
+	mustEqual(t, received[0].ID == "", false)
+
+	denied := *claims
+	denied.Subject = "denied"
+	err := transmitter.Push(ctx, srv.URL, &denied)
+	mustEqual(t, err, error(&SETDeliveryError{Code: "access_denied"}))
+
+	failed := *claims
+	failed.Subject = "failed"
+	err = transmitter.Push(ctx, srv.URL, &failed)
+	mustEqual(t, err, error(&HTTPStatusError{URL: srv.URL, StatusCode: http.StatusInternalServerError}))
+
+	evil := *claims
+	evil.Issuer = "https://evil.example.com"
+	err = transmitter.Push(ctx, srv.URL, &evil)
+	var deliveryErr *SETDeliveryError
+	mustEqual(t, errors.As(err, &deliveryErr), true)
+	mustEqual(t, deliveryErr.Code, "invalid_issuer")
+
+	another := NewSETTransmitter(must(NewSignerES(ES256, ecdsaPrivateKey256Another)), srv.Client())
+	err = another.Push(ctx, srv.URL, claims)
+	mustEqual(t, errors.As(err, &deliveryErr), true)
+	mustEqual(t, deliveryErr.Code, "invalid_key")
+}
+
+func TestSETReceiverBadRequest(t *testing.T) {
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+	receiver := NewSETReceiver(verifier, &SETValidator{Issuer: "tx"}, func(ctx context.Context, claims *SecurityEventClaims) error {
+		return nil
+	})
+
+	testCases := []struct {
+		method      string
+		contentType string
+		want        int
+	}{
+		{http.MethodGet, "application/secevent+jwt", http.StatusMethodNotAllowed},
+		{http.MethodPost, "application/json", http.StatusBadRequest},
+		{http.MethodPost, "application/secevent+jwt", http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		r := httptest.NewRequest(tc.method, "/events", strings.NewReader("not-a-token"))
+		r.Header.Set("Content-Type", tc.contentType)
+		w := httptest.NewRecorder()
+
+		receiver.ServeHTTP(w, r)
+		mustEqual(t, w.Code, tc.want)
+	}
+
+	// key fetch failure is not a token error.
+	receiver = NewSETReceiver(errVerifier{errors.New("keys unavailable")}, &SETValidator{Issuer: "tx"}, nil)
+	token := must(NewBuilder(must(NewSignerHS(HS256, hsKey256))).Build(simplePayload))
+	r := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(token.String()))
+	r.Header.Set("Content-Type", "application/secevent+jwt")
+	w := httptest.NewRecorder()
+	receiver.ServeHTTP(w, r)
+	mustEqual(t, w.Code, http.StatusInternalServerError)
+	mustEqual(t, w.Body.Len(), 0)
+}
+
+func TestSETValidator(t *testing.T) {
+	signer := must(NewSignerHS(HS256, hsKey256))
+	verifier := must(NewVerifierHS(HS256, hsKey256))
+	validator := &SETValidator{Issuer: "tx"}
+
+	testCases := []struct {
+		opts    []BuilderOption
+		claims  *SecurityEventClaims
+		wantErr error
+	}{
+		{
+			[]BuilderOption{WithType(TypeSecEvent)},
+			&SecurityEventClaims{RegisteredClaims: RegisteredClaims{ID: "1", Issuer: "tx", IssuedAt: NewNumericDate(time.Now())}, Events: map[string]json.RawMessage{testEvent: nil}},
+			nil,
+		},
+		{
+			nil,
+			&SecurityEventClaims{RegisteredClaims: RegisteredClaims{ID: "1", Issuer: "tx", IssuedAt: NewNumericDate(time.Now())}, Events: map[string]json.RawMessage{testEvent: nil}},
+			ErrTypeMismatch,
+		},
+		{
+			[]BuilderOption{WithType(TypeSecEvent)},
+			&SecurityEventClaims{RegisteredClaims: RegisteredClaims{Issuer: "tx", IssuedAt: NewNumericDate(time.Now())}, Events: map[string]json.RawMessage{testEvent: nil}},
+			ErrMissingID,
+		},
+		{
+			[]BuilderOption{WithType(TypeSecEvent)},
+			&SecurityEventClaims{RegisteredClaims: RegisteredClaims{ID: "1", Issuer: "tx"}, Events: map[string]json.RawMessage{testEvent: nil}},
+			ErrMissingClaim,
+		},
+		{
+			[]BuilderOption{WithType(TypeSecEvent)},
+			&SecurityEventClaims{RegisteredClaims: RegisteredClaims{ID: "1", Issuer: "tx", IssuedAt: NewNumericDate(time.Now())}},
+			ErrMissingClaim,
+		},
+		{
+			[]BuilderOption{WithType(TypeSecEvent)},
+			&SecurityEventClaims{RegisteredClaims: RegisteredClaims{ID: "1", IssuedAt: NewNumericDate(time.Now())}, Events: map[string]json.RawMessage{testEvent: nil}},
+			ErrMissingClaim,
+		},
+		{
+			[]BuilderOption{WithType(TypeSecEvent)},
+			&SecurityEventClaims{RegisteredClaims: RegisteredClaims{ID: "1", Issuer: "rx", IssuedAt: NewNumericDate(time.Now())}, Events: map[string]json.RawMessage{testEvent: nil}},
+			ErrIssuerMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		token := must(NewBuilder(signer, tc.opts...).Build(tc.claims))
+		_, err := validator.Parse(token.Bytes(), verifier)
+		mustEqual(t, errors.Is(err, tc.wantErr), true)
+	}
+
+	_, err := (&SETValidator{}).Parse(nil, verifier)
+	mustEqual(t, err, ErrInvalidConfig)
+}
@@ -0,0 +1,128 @@
+package jwt
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Validator is used to validate a token after it was parsed and it's signature was verified.
+type Validator interface {
+	Validate(ctx context.Context, token *Token) error
+}
+
+// ValidatorFunc is an adapter to use an ordinary function as a Validator.
+type ValidatorFunc func(ctx context.Context, token *Token) error
+
+// Validate implements Validator interface.
+func (f ValidatorFunc) Validate(ctx context.Context, token *Token) error {
+	return f(ctx, token)
+}
+
+// ParseAndValidate decodes a token, verifies it's signature and runs validators in order.
+// First validation error is returned.
+func ParseAndValidate(ctx context.Context, raw []byte, verifier Verifier, validators ...Validator) (*Token, error) {
+	token, err := Parse(raw, verifier)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range validators {
+		if err := v.Validate(ctx, token); err != nil {
+			return nil, err
+		}
+	}
+	return token, nil
+}
+
+// claimErrors collects failed claims to report all of them in a ValidationError.
+type claimErrors []*ClaimError
+
+// check adds a failed claim if ok is false.
+func (e *claimErrors) check(ok bool, claim string, err error) {
+	if !ok {
+		*e = append(*e, &ClaimError{Claim: claim, Err: err})
+	}
+}
+
+// require adds ErrMissingClaim if a claim is not present.
+func (e *claimErrors) require(present bool, claim string) {
+	e.check(present, claim, ErrMissingClaim)
+}
+
+// checkTime checks `exp`, `nbf` and `iat` claims with a given leeway.
+func (e *claimErrors) checkTime(claims *RegisteredClaims, now time.Time, leeway time.Duration) {
+	e.check(claims.IsValidExpiresAt(now.Add(-leeway)), "exp", ErrTokenExpired)
+	e.check(claims.IsValidNotBefore(now.Add(leeway)), "nbf", ErrTokenNotValidYet)
+	e.check(claims.IsValidIssuedAt(now.Add(leeway)), "iat", ErrTokenNotValidYet)
+}
+
+// checkAge checks that `iat` claim is not older than maxAge, missing claim is ignored.
+func (e *claimErrors) checkAge(iat *NumericDate, now time.Time, maxAge, leeway time.Duration) {
+	e.check(iat == nil || !iat.Add(maxAge+leeway).Before(now), "iat", ErrTokenExpired)
+}
+
+// err returns a ValidationError if any claim failed.
+func (e claimErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return &ValidationError{Claims: e}
+}
+
+// AnyType is an allowed type that disables explicit typing check.
+const AnyType = "*"
+
+// NewTypeValidator returns a Validator that requires token `typ` header to be one of given values.
+// See WithAllowedTypes for matching rules.
+func NewTypeValidator(types ...string) Validator {
+	return ValidatorFunc(func(ctx context.Context, token *Token) error {
+		return checkType(token.Header(), types)
+	})
+}
+
+// checkType returns ErrTypeMismatch if token header has none of given types.
+// Empty type matches a token without `typ` header, AnyType matches every token.
+func checkType(header Header, types []string) error {
+	for _, typ := range types {
+		if typ == AnyType || hasType(header, typ) {
+			return nil
+		}
+	}
+	return ErrTypeMismatch
+}
+
+// typesOr returns allowed types or defaults if types are empty.
+func typesOr(types []string, defaults ...string) []string {
+	if len(types) > 0 {
+		return types
+	}
+	return defaults
+}
+
+// hasType reports whether token header has a given media type.
+// Comparison is case-insensitive and `application/` prefix is optional on both sides.
+// See: https://datatracker.ietf.org/doc/html/rfc7515#section-4.1.9
+func hasType(header Header, typ string) bool {
+	return strings.EqualFold(trimApplication(header.Type), trimApplication(typ))
+}
+
+// trimApplication removes `application/` prefix of a media type.
+func trimApplication(typ string) string {
+	const prefix = "application/"
+	if len(typ) > len(prefix) && strings.EqualFold(typ[:len(prefix)], prefix) {
+		return typ[len(prefix):]
+	}
+	return typ
+}
+
+// missingClaim returns ErrMissingClaim for a single claim.
+func missingClaim(name string) error {
+	return &ClaimError{Claim: name, Err: ErrMissingClaim}
+}
+
+func nowFunc(now func() time.Time) time.Time {
+	if now == nil {
+		return time.Now()
+	}
+	return now()
+}